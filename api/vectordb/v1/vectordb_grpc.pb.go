@@ -0,0 +1,414 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: vectordb/v1/vectordb.proto
+
+package vectordbv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	VectorDB_CreateIndex_FullMethodName = "/vectordb.v1.VectorDB/CreateIndex"
+	VectorDB_Insert_FullMethodName      = "/vectordb.v1.VectorDB/Insert"
+	VectorDB_BulkInsert_FullMethodName  = "/vectordb.v1.VectorDB/BulkInsert"
+	VectorDB_Search_FullMethodName      = "/vectordb.v1.VectorDB/Search"
+	VectorDB_BatchSearch_FullMethodName = "/vectordb.v1.VectorDB/BatchSearch"
+	VectorDB_Delete_FullMethodName      = "/vectordb.v1.VectorDB/Delete"
+	VectorDB_GetStats_FullMethodName    = "/vectordb.v1.VectorDB/GetStats"
+)
+
+// VectorDBClient is the client API for VectorDB service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VectorDBClient interface {
+	// Create the index, ALREADY_EXISTS if it was already created
+	CreateIndex(ctx context.Context, in *IndexConfig, opts ...grpc.CallOption) (*IndexConfig, error)
+	// Insert a batch of vectors, ALREADY_EXISTS if an id exists and upsert is not set, RESOURCE_EXHAUSTED beyond the
+	// memory limit
+	Insert(ctx context.Context, in *InsertRequest, opts ...grpc.CallOption) (*InsertResponse, error)
+	// Insert batches as they are received, batches before a failed one stay inserted
+	BulkInsert(ctx context.Context, opts ...grpc.CallOption) (VectorDB_BulkInsertClient, error)
+	// k-NN search
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// k-NN search of each query received, responses are sent in request order
+	BatchSearch(ctx context.Context, opts ...grpc.CallOption) (VectorDB_BatchSearchClient, error)
+	// Delete vectors by id, unknown ids are skipped
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Index parameters and the connections of each level
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error)
+}
+
+type vectorDBClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVectorDBClient(cc grpc.ClientConnInterface) VectorDBClient {
+	return &vectorDBClient{cc}
+}
+
+func (c *vectorDBClient) CreateIndex(ctx context.Context, in *IndexConfig, opts ...grpc.CallOption) (*IndexConfig, error) {
+	out := new(IndexConfig)
+	err := c.cc.Invoke(ctx, VectorDB_CreateIndex_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorDBClient) Insert(ctx context.Context, in *InsertRequest, opts ...grpc.CallOption) (*InsertResponse, error) {
+	out := new(InsertResponse)
+	err := c.cc.Invoke(ctx, VectorDB_Insert_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorDBClient) BulkInsert(ctx context.Context, opts ...grpc.CallOption) (VectorDB_BulkInsertClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VectorDB_ServiceDesc.Streams[0], VectorDB_BulkInsert_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vectorDBBulkInsertClient{stream}
+	return x, nil
+}
+
+type VectorDB_BulkInsertClient interface {
+	Send(*InsertRequest) error
+	CloseAndRecv() (*InsertResponse, error)
+	grpc.ClientStream
+}
+
+type vectorDBBulkInsertClient struct {
+	grpc.ClientStream
+}
+
+func (x *vectorDBBulkInsertClient) Send(m *InsertRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *vectorDBBulkInsertClient) CloseAndRecv() (*InsertResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(InsertResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *vectorDBClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, VectorDB_Search_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorDBClient) BatchSearch(ctx context.Context, opts ...grpc.CallOption) (VectorDB_BatchSearchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VectorDB_ServiceDesc.Streams[1], VectorDB_BatchSearch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vectorDBBatchSearchClient{stream}
+	return x, nil
+}
+
+type VectorDB_BatchSearchClient interface {
+	Send(*SearchRequest) error
+	Recv() (*SearchResponse, error)
+	grpc.ClientStream
+}
+
+type vectorDBBatchSearchClient struct {
+	grpc.ClientStream
+}
+
+func (x *vectorDBBatchSearchClient) Send(m *SearchRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *vectorDBBatchSearchClient) Recv() (*SearchResponse, error) {
+	m := new(SearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *vectorDBClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, VectorDB_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorDBClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, VectorDB_GetStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VectorDBServer is the server API for VectorDB service.
+// All implementations must embed UnimplementedVectorDBServer
+// for forward compatibility
+type VectorDBServer interface {
+	// Create the index, ALREADY_EXISTS if it was already created
+	CreateIndex(context.Context, *IndexConfig) (*IndexConfig, error)
+	// Insert a batch of vectors, ALREADY_EXISTS if an id exists and upsert is not set, RESOURCE_EXHAUSTED beyond the
+	// memory limit
+	Insert(context.Context, *InsertRequest) (*InsertResponse, error)
+	// Insert batches as they are received, batches before a failed one stay inserted
+	BulkInsert(VectorDB_BulkInsertServer) error
+	// k-NN search
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// k-NN search of each query received, responses are sent in request order
+	BatchSearch(VectorDB_BatchSearchServer) error
+	// Delete vectors by id, unknown ids are skipped
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Index parameters and the connections of each level
+	GetStats(context.Context, *GetStatsRequest) (*Stats, error)
+	mustEmbedUnimplementedVectorDBServer()
+}
+
+// UnimplementedVectorDBServer must be embedded to have forward compatible implementations.
+type UnimplementedVectorDBServer struct {
+}
+
+func (UnimplementedVectorDBServer) CreateIndex(context.Context, *IndexConfig) (*IndexConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIndex not implemented")
+}
+func (UnimplementedVectorDBServer) Insert(context.Context, *InsertRequest) (*InsertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Insert not implemented")
+}
+func (UnimplementedVectorDBServer) BulkInsert(VectorDB_BulkInsertServer) error {
+	return status.Errorf(codes.Unimplemented, "method BulkInsert not implemented")
+}
+func (UnimplementedVectorDBServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedVectorDBServer) BatchSearch(VectorDB_BatchSearchServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchSearch not implemented")
+}
+func (UnimplementedVectorDBServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedVectorDBServer) GetStats(context.Context, *GetStatsRequest) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedVectorDBServer) mustEmbedUnimplementedVectorDBServer() {}
+
+// UnsafeVectorDBServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VectorDBServer will
+// result in compilation errors.
+type UnsafeVectorDBServer interface {
+	mustEmbedUnimplementedVectorDBServer()
+}
+
+func RegisterVectorDBServer(s grpc.ServiceRegistrar, srv VectorDBServer) {
+	s.RegisterService(&VectorDB_ServiceDesc, srv)
+}
+
+func _VectorDB_CreateIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IndexConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorDBServer).CreateIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorDB_CreateIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorDBServer).CreateIndex(ctx, req.(*IndexConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorDB_Insert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorDBServer).Insert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorDB_Insert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorDBServer).Insert(ctx, req.(*InsertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorDB_BulkInsert_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VectorDBServer).BulkInsert(&vectorDBBulkInsertServer{stream})
+}
+
+type VectorDB_BulkInsertServer interface {
+	SendAndClose(*InsertResponse) error
+	Recv() (*InsertRequest, error)
+	grpc.ServerStream
+}
+
+type vectorDBBulkInsertServer struct {
+	grpc.ServerStream
+}
+
+func (x *vectorDBBulkInsertServer) SendAndClose(m *InsertResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *vectorDBBulkInsertServer) Recv() (*InsertRequest, error) {
+	m := new(InsertRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _VectorDB_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorDBServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorDB_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorDBServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorDB_BatchSearch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VectorDBServer).BatchSearch(&vectorDBBatchSearchServer{stream})
+}
+
+type VectorDB_BatchSearchServer interface {
+	Send(*SearchResponse) error
+	Recv() (*SearchRequest, error)
+	grpc.ServerStream
+}
+
+type vectorDBBatchSearchServer struct {
+	grpc.ServerStream
+}
+
+func (x *vectorDBBatchSearchServer) Send(m *SearchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *vectorDBBatchSearchServer) Recv() (*SearchRequest, error) {
+	m := new(SearchRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _VectorDB_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorDBServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorDB_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorDBServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorDB_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorDBServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorDB_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorDBServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VectorDB_ServiceDesc is the grpc.ServiceDesc for VectorDB service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VectorDB_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vectordb.v1.VectorDB",
+	HandlerType: (*VectorDBServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateIndex",
+			Handler:    _VectorDB_CreateIndex_Handler,
+		},
+		{
+			MethodName: "Insert",
+			Handler:    _VectorDB_Insert_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _VectorDB_Search_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _VectorDB_Delete_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _VectorDB_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkInsert",
+			Handler:       _VectorDB_BulkInsert_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BatchSearch",
+			Handler:       _VectorDB_BatchSearch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "vectordb/v1/vectordb.proto",
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+)
+
+// Load up to `num` vectors from a TEXMEX or NumPy file, selecting the format by extension (0 to load all)
+func loadVectors(filename string, num int) (vec [][]float32, err error) {
+
+	if num <= 0 {
+		num = -1
+	}
+
+	switch filepath.Ext(filename) {
+
+	case ".fvecs":
+		return vectors.ReadFvecsRange(filename, 0, num)
+
+	case ".bvecs":
+		bvec, err := vectors.ReadBvecsRange(filename, 0, num)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return vectors.BvecsToFloat32(bvec), nil
+
+	case ".npy":
+		vec, err := vectors.LoadNPY(filename)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if num > 0 && num < len(vec) {
+			vec = vec[:num]
+		}
+
+		return vec, nil
+
+	}
+
+	return nil, fmt.Errorf("unsupported vector file format (%s), expected .fvecs, .bvecs or .npy", filename)
+
+}
+
+// Insert the base vectors with `numWorkers` concurrent inserts, returns the node id of each base vector. Node ids are
+// assigned in the order the inserts run, not the order of the base vectors
+func insertBase(h *hnsw.HNSW, vec [][]float32, numWorkers int) (nodes []uint32, err error) {
+
+	nodes = make([]uint32, len(vec))
+
+	jobs := make(chan int, numWorkers)
+	errs := make(chan error, numWorkers)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < numWorkers; i++ {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			for i := range jobs {
+
+				id, err := h.Insert(vec[i])
+
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				nodes[i] = id
+
+			}
+
+		}()
+
+	}
+
+	for i := range vec {
+
+		if i%1000 == 0 {
+			// Clear the current line
+			fmt.Printf("\033[2K\r")
+			fmt.Printf("Added %d records", i)
+		}
+
+		select {
+		case jobs <- i:
+		case err = <-errs:
+		}
+
+		if err != nil {
+			break
+		}
+
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err == nil && len(errs) > 0 {
+		err = <-errs
+	}
+
+	return nodes, err
+
+}
+
+// Rerank from the base vectors on disk once the in-memory vectors are released. Node 0 is the null entry-point created
+// by hnsw.New, other nodes are looked up in the base vector index of each node
+type baseFileStore struct {
+	file  *vectors.FvecsFile
+	bases []int // Base vector of each node id, -1 for the entry-point
+}
+
+// Store reading the base vectors from `file`, `nodes` holds the node id of each base vector as returned by insertBase
+func newBaseFileStore(file *vectors.FvecsFile, nodes []uint32) *baseFileStore {
+
+	size := 1
+
+	for _, id := range nodes {
+		size = max(size, int(id)+1)
+	}
+
+	s := &baseFileStore{file: file, bases: make([]int, size)}
+
+	for i := range s.bases {
+		s.bases[i] = -1
+	}
+
+	for i, id := range nodes {
+		s.bases[id] = i
+	}
+
+	return s
+
+}
+
+func (s *baseFileStore) Vector(id uint32) ([]float32, error) {
+
+	if int(id) >= len(s.bases) || s.bases[id] < 0 {
+		return make([]float32, s.file.Dim()), nil
+	}
+
+	return s.file.ReadAt(s.bases[id])
+
+}
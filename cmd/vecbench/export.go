@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+)
+
+// Run the `export` subcommand, write a level of a saved index (or one built from -base-file or random vectors) as
+// Graphviz DOT, GraphML or a CSV edge list. The format is chosen by the extension of -out unless -format is set
+//
+//	vecbench export -index data/vector.gob -level 0 -max-nodes 500 -projection -out graph.dot
+//	vecbench export -num 2000 -size 16 -level -1 -out graph.graphml
+func runExport(args []string) {
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+
+	index := flags.String("index", "", "Index saved with -save, instead of building one")
+	baseFile := flags.String("base-file", "", "Build the index from a .fvecs, .bvecs or .npy file instead of random vectors")
+	vecNum := flags.Int("num", 1000, "Number of vectors to build the index from (max number read with -base-file, 0 for all)")
+	vecDim := flags.Int("size", 16, "Random vector dimensions")
+	m := flags.Int("m", 8, "Number of established connections")
+	mmax := flags.Int("mmax", 8, "Max number of graph connections")
+	mmax0 := flags.Int("mmax0", 16, "Max number of graph connections at layer 0")
+	ef := flags.Int("ef", 200, "Size of the dynamic candidate list during index creation")
+
+	level := flags.Int("level", 0, "Level to export, -1 for all levels")
+	maxNodes := flags.Int("max-nodes", 1000, "Keep the nodes reached first breadth first from the entry point, 0 for all")
+	projection := flags.Bool("projection", false, "Add the 2D projection of each node's vector (pos in DOT, x and y otherwise)")
+	format := flags.String("format", "", "Output format (dot, graphml, csv), from the -out extension if empty")
+	out := flags.String("out", "", "Output file (.dot, .graphml or .csv)")
+
+	flags.Parse(args)
+
+	if *out == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+
+		switch filepath.Ext(*out) {
+		case ".dot", ".gv":
+			*format = "dot"
+		case ".graphml":
+			*format = "graphml"
+		case ".csv":
+			*format = "csv"
+		default:
+			log.Fatalf("Cannot tell the format of %s, set -format", *out)
+		}
+
+	}
+
+	var h hnsw.HNSW
+	var err error
+
+	if *index != "" {
+
+		h, err = hnsw.Load(*index)
+
+	} else {
+
+		vec := [][]float32{}
+
+		if *baseFile != "" {
+			vec, err = loadVectors(*baseFile, *vecNum)
+		} else {
+			vec, err = vectors.GenerateRandomVectors(*vecNum, *vecDim)
+		}
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(vec) == 0 {
+			log.Fatal("No vectors to build the index from")
+		}
+
+		h, err = hnsw.New(*m, *mmax, *mmax0, *ef, len(vec[0]))
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for i := range vec {
+
+			_, err = h.Insert(vec[i])
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+		}
+
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Create(*out)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options := hnsw.ExportOptions{Level: *level, MaxNodes: *maxNodes, Projection: *projection}
+
+	var nodes, edges int
+
+	switch *format {
+	case "dot":
+		nodes, edges, err = h.ExportDOT(file, options)
+	case "graphml":
+		nodes, edges, err = h.ExportGraphML(file, options)
+	case "csv":
+		nodes, edges, err = h.ExportEdgeList(file, options)
+	default:
+		log.Fatalf("Unknown format (%s)", *format)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = file.Close()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	levels := fmt.Sprintf("level %d", *level)
+
+	if *level == hnsw.AllLevels {
+		levels = "all levels"
+	}
+
+	fmt.Printf("Exported %s of the graph (%d nodes, %d edges, max level %d) to %s\n", levels, nodes, edges, h.Maxlevel, *out)
+
+}
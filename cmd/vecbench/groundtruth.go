@@ -0,0 +1,229 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+)
+
+// Run the `groundtruth` subcommand, compute the exact k-NN of each query and write the results as .ivecs, with the
+// distances in a matching .fvecs file. Ground truth files hold base vector indices (ordered nearest first) per query, as
+// used by SIFT1M/GIST1M, translated to HNSW node ids by groundTruthNodes
+//
+//	vecbench groundtruth -base-file sift_base.fvecs -query-file sift_query.fvecs -k 100 -out sift_groundtruth.ivecs
+func runGroundTruth(args []string) {
+
+	flags := flag.NewFlagSet("groundtruth", flag.ExitOnError)
+
+	baseFile := flags.String("base-file", "", "Base vectors (.fvecs, .bvecs or .npy)")
+	queryFile := flags.String("query-file", "", "Query vectors (.fvecs, .bvecs or .npy), defaults to the base vectors")
+	vecNum := flags.Int("num", 0, "Max number of base vectors to read, 0 for all")
+	k := flags.Int("k", 100, "Number of nearest neighbours to record per query")
+	out := flags.String("out", "", "Output ground truth file (.ivecs), distances are written to the matching .dist.fvecs")
+
+	flags.Parse(args)
+
+	if *baseFile == "" || *out == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	base, err := loadVectors(*baseFile, *vecNum)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	queries := base
+
+	if *queryFile != "" {
+
+		queries, err = loadVectors(*queryFile, 0)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	}
+
+	if *k > len(base) {
+		log.Fatalf("k (%d) exceeds the number of base vectors (%d)", *k, len(base))
+	}
+
+	fmt.Printf("Computing ground truth for (%d) queries over (%d) base vectors. Returning (%d-NN) hits\n", len(queries), len(base), *k)
+
+	start := time.Now()
+
+	ids, dists := computeGroundTruth(base, queries, *k, runtime.NumCPU())
+
+	fmt.Printf("\nGround truth complete in %0.6f (secs)\n", time.Since(start).Seconds())
+
+	err = vectors.WriteIvecs(*out, ids)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = vectors.WriteFvecs(groundTruthDistanceFile(*out), dists)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Ground truth written to %s (distances %s)\n", *out, groundTruthDistanceFile(*out))
+
+}
+
+// Exact k-NN by brute force, returns base indices and distances for each query ordered nearest first
+func computeGroundTruth(base [][]float32, queries [][]float32, k int, numWorkers int) (ids [][]int32, dists [][]float32) {
+
+	ids = make([][]int32, len(queries))
+	dists = make([][]float32, len(queries))
+
+	jobs := make(chan int, numWorkers)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < numWorkers; i++ {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			for q := range jobs {
+
+				topCandidates := queue.NewTopK(k)
+
+				for i2 := range base {
+
+					nodeDist, _ := distance.L2_Opt(&queries[q], &base[i2])
+
+					topCandidates.Push(queue.Item{Node: uint32(i2), Distance: nodeDist})
+
+				}
+
+				ids[q] = make([]int32, topCandidates.Len())
+				dists[q] = make([]float32, topCandidates.Len())
+
+				for i2, item := range topCandidates.Drain(nil) {
+					ids[q][i2] = int32(item.Node)
+					dists[q][i2] = item.Distance
+				}
+
+			}
+
+		}()
+
+	}
+
+	for q := range queries {
+
+		jobs <- q
+
+		if q%1000 == 0 {
+			// Clear the current line
+			fmt.Printf("\033[2K\r")
+			fmt.Printf("Searched %d records", q)
+		}
+
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return
+
+}
+
+// Load cached ground truth, converting base indices to HNSW node ids (`nodes` holds the node id of each base vector), and
+// keeping the first k results per query
+//
+// Distances are loaded from the matching .dist.fvecs if it exists, otherwise groundDists is nil.
+func loadGroundTruth(filename string, numQueries int, k int, nodes []uint32) (groundResults [][]uint32, groundDists [][]float32, err error) {
+
+	ids, err := vectors.ReadIvecs(filename)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(ids) != numQueries {
+		return nil, nil, fmt.Errorf("ground truth has %d queries, expected %d", len(ids), numQueries)
+	}
+
+	for i := range ids {
+
+		if len(ids[i]) < k {
+			return nil, nil, fmt.Errorf("ground truth has %d results for query %d, k is %d", len(ids[i]), i, k)
+		}
+
+		ids[i] = ids[i][:k]
+
+	}
+
+	groundResults, err = groundTruthNodes(ids, nodes)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	distFile := groundTruthDistanceFile(filename)
+
+	if _, err := os.Stat(distFile); err != nil {
+		return groundResults, nil, nil
+	}
+
+	groundDists, err = vectors.ReadFvecs(distFile)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(groundDists) != len(ids) {
+		return nil, nil, fmt.Errorf("ground truth distances have %d queries, expected %d", len(groundDists), len(ids))
+	}
+
+	return groundResults, groundDists, nil
+
+}
+
+// Translate the base indices of each query to HNSW node ids, `nodes` holds the node id of each base vector
+func groundTruthNodes(ids [][]int32, nodes []uint32) (groundResults [][]uint32, err error) {
+
+	groundResults = make([][]uint32, len(ids))
+
+	for i := range ids {
+
+		groundResults[i] = make([]uint32, len(ids[i]))
+
+		for i2, id := range ids[i] {
+
+			if id < 0 || int(id) >= len(nodes) {
+				return nil, fmt.Errorf("ground truth base vector %d of query %d exceeds the %d base vectors", id, i, len(nodes))
+			}
+
+			groundResults[i][i2] = nodes[id]
+
+		}
+
+	}
+
+	return groundResults, nil
+
+}
+
+// Distances are stored alongside the ground truth, e.g sift_groundtruth.ivecs => sift_groundtruth.dist.fvecs
+func groundTruthDistanceFile(filename string) string {
+	return strings.TrimSuffix(filename, ".ivecs") + ".dist.fvecs"
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GroundTruthNodes(t *testing.T) {
+
+	vec, err := vectors.GenerateRandomVectors(2000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vec[0]))
+	assert.Nil(t, err)
+
+	// Concurrent inserts assign node ids in the order they run
+	nodes, err := insertBase(&h, vec, 8)
+	assert.Nil(t, err)
+
+	seen := map[uint32]bool{}
+
+	for i := range vec {
+		assert.Equal(t, vec[i], h.Vector(nodes[i]))
+		seen[nodes[i]] = true
+	}
+
+	assert.Equal(t, len(vec), len(seen))
+
+	// Each base vector is its own nearest neighbour, the ground truth translates to its node
+	ids, dists := computeGroundTruth(vec, vec[:100], 10, 4)
+
+	filename := filepath.Join(t.TempDir(), "groundtruth.ivecs")
+
+	assert.Nil(t, vectors.WriteIvecs(filename, ids))
+	assert.Nil(t, vectors.WriteFvecs(groundTruthDistanceFile(filename), dists))
+
+	groundResults, groundDists, err := loadGroundTruth(filename, 100, 5, nodes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, dists, groundDists)
+
+	for q := range groundResults {
+
+		assert.Equal(t, 5, len(groundResults[q]))
+		assert.Equal(t, nodes[q], groundResults[q][0])
+
+		for i, id := range groundResults[q] {
+			assert.Equal(t, vec[ids[q][i]], h.Vector(id))
+		}
+
+	}
+
+	// Base indices beyond the base vectors
+	_, err = groundTruthNodes([][]int32{{int32(len(vec))}}, nodes)
+	assert.NotNil(t, err)
+
+}
+
+func Test_BaseFileStore(t *testing.T) {
+
+	vec, err := vectors.GenerateRandomVectors(500, 8)
+	assert.Nil(t, err)
+
+	filename := filepath.Join(t.TempDir(), "base.fvecs")
+	assert.Nil(t, vectors.WriteFvecs(filename, vec))
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vec[0]))
+	assert.Nil(t, err)
+
+	nodes, err := insertBase(&h, vec, 4)
+	assert.Nil(t, err)
+
+	file, err := vectors.OpenFvecsFile(filename)
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	store := newBaseFileStore(file, nodes)
+
+	// Each node reads the base vector inserted as it
+	for id := 1; id <= len(vec); id++ {
+
+		v, err := store.Vector(uint32(id))
+
+		assert.Nil(t, err)
+		assert.Equal(t, h.Vector(uint32(id)), v)
+
+	}
+
+	v, err := store.Vector(0)
+	assert.Nil(t, err)
+	assert.Equal(t, make([]float32, 8), v)
+
+}
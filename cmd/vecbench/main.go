@@ -1,28 +1,29 @@
 package main
 
 import (
-	"container/heap"
 	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/pprof"
 	"strings"
 	"time"
 
 	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
-	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
 	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
 
 	. "github.com/klauspost/cpuid/v2"
 )
 
 type Stats struct {
-	Dim  int
-	Size int
-	K    int
+	Dim     int
+	Size    int
+	Queries int
+	K       int
 
 	M         int
 	Mmax      int
@@ -31,6 +32,16 @@ type Stats struct {
 	Heuristic bool
 	EfSearch  int
 
+	VectorType   string
+	Quantization string
+	VectorBytes  int64 // Full precision vectors
+	CodeBytes    int64 // Quantized vectors used for graph traversal
+
+	Graph hnsw.GraphStats // Levels and degree distribution of the built graph, JSON only
+
+	Orphans     int // Nodes on level 0 with no incoming links after the build, JSON only
+	Unreachable int // Nodes on level 0 not reachable from the entry point after the build, JSON only
+
 	CpuType           string
 	CpuPhysicalCores  int
 	CpuThreadsPerCore int
@@ -52,19 +63,44 @@ type Stats struct {
 	GroundTruthHits int
 	HNSWPrecision   float64
 
+	Recall1       float64 // Fraction of queries where the nearest neighbour is the first result
+	Recall10      float64 // Recall of the first 10 results against the true 10-NN (k >= 10)
+	RecallK       float64 // Recall of the k results against the true k-NN
+	MeanDistRatio float64 // Mean ratio of result distance to true neighbour distance at each rank (1.0 is exact)
+
+	LatencyP50Ms  float64
+	LatencyP90Ms  float64
+	LatencyP99Ms  float64
+	LatencyP999Ms float64
+
 	DateStart time.Time
 	DateEnd   time.Time
 }
 
 func main() {
 
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "groundtruth" {
+		runGroundTruth(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+
 	profile := flag.String("profile", "", "Set to enabling profiling with specified filename (default.pgo)")
 
 	csvfile := flag.String("csvfile", "", "Export results to CSV file (stats.csv)")
+	jsonfile := flag.String("jsonfile", "", "Export results to a JSON lines file, one object per efSearch (stats.jsonl)")
 	save := flag.String("save", "", "Export index to disk (data/vector.gob")
 
 	vecDim := flag.Int("size", 32, "Set vector dimensions")
-	vecNum := flag.Int("num", 1024, "Set number of vectors")
+	vecNum := flag.Int("num", 1024, "Set number of vectors (max number read with -base-file, 0 for all)")
+	baseFile := flag.String("base-file", "", "Load base vectors from a .fvecs, .bvecs or .npy file instead of random vectors")
+	numQueries := flag.Int("queries", 0, "Number of random query vectors, separate from the base vectors (max number read with -query-file, 0 to query the base vectors)")
+	queryFile := flag.String("query-file", "", "Load query vectors from a .fvecs, .bvecs or .npy file")
 	k := flag.Int("k", 10, "Number of results to return for k-NN")
 
 	m := flag.Int("m", 8, "Max number of HNSW layers")
@@ -72,20 +108,89 @@ func main() {
 	mmax0 := flag.Int("mmax0", 16, "Max number of graph connections at layer 0")
 	ef := flag.Int("ef", 200, "Size of the dynamic candidate list during index creation")
 	heuristic := flag.Bool("heuristic", true, "Enable HNSW heuristic for neighbour selection")
+	vectorType := flag.String("vector-type", "float32", "Precision used to store vectors in the index (float32, float16, bfloat16)")
+	quantize := flag.String("quantization", "none", "Quantize vectors for graph traversal during search (none, int8, pq, binary)")
+	quantizeSample := flag.Int("quantization-sample", 10000, "Number of base vectors used to train the quantizer")
+	rerankMetric := flag.String("rerank-metric", "l2", "Distance used to rerank quantized search candidates (l2, cosine)")
+	pqM := flag.Int("pq-m", 8, "Number of PQ subspaces (bytes per vector), must divide the vector dimensions")
+	releaseVectors := flag.Bool("release-vectors", false, "Release the in-memory vectors after the build and rerank from -base-file (.fvecs, requires -quantization)")
 
 	groundtruth := flag.Bool("groundtruth", true, "Compare HNSW results with brute force (ground truth)")
+	groundtruthFile := flag.String("groundtruth-file", "", "Load ground truth (.ivecs) created by `vecbench groundtruth` instead of a brute force search")
 	hnswsearch := flag.Bool("hnswsearch", true, "Search using HNSW algorithm")
+	reconnect := flag.Bool("reconnect", false, "Link the orphaned and unreachable nodes of the built graph back, see HNSW.Reconnect")
 
 	var newFile bool = false
 
 	flag.Parse()
 
+	var vec [][]float32
+
+	if *baseFile != "" {
+
+		var err error
+
+		vec, err = loadVectors(*baseFile, *vecNum)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(vec) == 0 {
+			log.Fatalf("No vectors found in %s", *baseFile)
+		}
+
+		*vecNum = len(vec)
+		*vecDim = len(vec[0])
+
+	} else {
+		vec, _ = vectors.GenerateRandomVectors(*vecNum, *vecDim)
+	}
+
+	// Query the base vectors by default, each query then finds itself which inflates recall
+	queries := vec
+
+	if *queryFile != "" {
+
+		var err error
+
+		queries, err = loadVectors(*queryFile, *numQueries)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(queries) == 0 {
+			log.Fatalf("No vectors found in %s", *queryFile)
+		}
+
+	} else if *numQueries > 0 {
+		queries, _ = vectors.GenerateRandomVectors(*numQueries, *vecDim)
+	}
+
+	if len(queries[0]) != *vecDim {
+		log.Fatalf("Query vectors have %d dimensions, base vectors have %d", len(queries[0]), *vecDim)
+	}
+
+	if *releaseVectors && filepath.Ext(*baseFile) != ".fvecs" {
+		log.Fatal("-release-vectors requires a .fvecs -base-file to rerank from")
+	}
+
+	if *groundtruthFile != "" && *baseFile == "" {
+		log.Fatal("-groundtruth-file requires -base-file, random vectors differ on each run")
+	}
+
+	if *groundtruthFile != "" && *queryFile == "" {
+		log.Fatal("-groundtruth-file requires -query-file, the ground truth is only valid for the queries it was computed with")
+	}
+
 	csvWriter := &csv.Writer{}
 
 	stats := Stats{}
 
 	stats.Dim = *vecDim
 	stats.Size = *vecNum
+	stats.Queries = len(queries)
 	stats.K = *k
 
 	stats.M = *m
@@ -93,6 +198,8 @@ func main() {
 	stats.Mmax0 = *mmax0
 	stats.Ef = *ef
 	stats.Heuristic = *heuristic
+	stats.VectorType = *vectorType
+	stats.Quantization = *quantize
 
 	stats.DateStart = time.Now()
 
@@ -130,6 +237,23 @@ func main() {
 
 	}
 
+	var jsonEncoder *json.Encoder
+
+	if *jsonfile != "" {
+
+		// Append a JSON object per line, so multiple runs can be compared
+		file, err := os.OpenFile(*jsonfile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o660)
+
+		if err != nil {
+			log.Fatal("Error creating file:", err)
+		}
+
+		defer file.Close()
+
+		jsonEncoder = json.NewEncoder(file)
+
+	}
+
 	// Create a CSV writer
 
 	if *profile != "" {
@@ -143,7 +267,7 @@ func main() {
 			log.Fatal(perr)
 		}
 
-		f2, err := os.Create(fmt.Sprintf("%s.mem", profile))
+		f2, err := os.Create(fmt.Sprintf("%s.mem", *profile))
 
 		if err != nil {
 			log.Fatal(err)
@@ -157,10 +281,41 @@ func main() {
 
 	}
 
-	vec, _ := vectors.GenerateRandomVectors(*vecNum, *vecDim)
-
 	// Init our HNSW Graph
-	h, err := hnsw.New(*m, *mmax, *mmax0, *ef, len(vec[0]))
+	options := make([]hnsw.Option, 0)
+
+	switch *vectorType {
+	case "float32":
+	case "float16":
+		options = append(options, hnsw.WithVectorType(hnsw.VectorFloat16))
+	case "bfloat16":
+		options = append(options, hnsw.WithVectorType(hnsw.VectorBFloat16))
+	default:
+		log.Fatalf("Unknown vector type (%s)", *vectorType)
+	}
+
+	switch *quantize {
+	case "none":
+	case "int8":
+		options = append(options, hnsw.WithScalarQuantizer(vec[:min(*quantizeSample, len(vec))]))
+	case "pq":
+		options = append(options, hnsw.WithProductQuantizer(vec[:min(*quantizeSample, len(vec))], *pqM))
+	case "binary":
+		options = append(options, hnsw.WithBinaryQuantizer(vec[:min(*quantizeSample, len(vec))], hnsw.MetricL2))
+	default:
+		log.Fatalf("Unknown quantization (%s)", *quantize)
+	}
+
+	h, err := hnsw.New(*m, *mmax, *mmax0, *ef, len(vec[0]), options...)
+
+	switch *rerankMetric {
+	case "l2":
+		h.RerankMetric = hnsw.MetricL2
+	case "cosine":
+		h.RerankMetric = hnsw.MetricCosine
+	default:
+		log.Fatalf("Unknown rerank metric (%s)", *rerankMetric)
+	}
 
 	// Use heurisitc
 	h.Heuristic = *heuristic
@@ -175,74 +330,132 @@ func main() {
 
 	fmt.Printf("Running benchmarks on CPU (%s)\n", CPU.BrandName)
 
-	fmt.Printf("Creating HNSW index with %d vectors (%d dimensions)\n", *vecNum, *vecDim)
+	fmt.Printf("Creating HNSW index with %d vectors (%d dimensions), %d queries\n", *vecNum, *vecDim, len(queries))
 
-	resultChan, jobs, err := h.InsertConcurrent(len(vec))
+	// Node id of each base vector, to translate the ground truth base indices
+	nodes, err := insertBase(&h, vec, runtime.NumCPU())
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for i := 1; i <= len(vec)-1; i++ {
-		//_, err := h.Insert(vec[i])
+	fmt.Println()
 
-		if i%1000 == 0 {
-			// Clear the current line
-			fmt.Printf("\033[2K\r")
-			fmt.Printf("Added %d records", i)
-		}
+	end := time.Since(start)
 
-		jobs <- vec[i]
+	// Recall is measured over the query set only
+	numQ := len(queries)
 
-		//fmt.Println(i)
+	stats.IndexBuildSecs = end.Seconds()
+	stats.IndexBuildMulti = float64(len(vec)) / end.Seconds()
+	stats.IndexBuildSingle = float64(len(vec)) / end.Seconds() / float64(runtime.NumCPU())
+
+	fmt.Printf("HNSW Graph built in %0.6f (secs)\n", stats.IndexBuildSecs)
+	fmt.Printf("HNSW Graph inserts per second %0.6f (%d threaded)\n", stats.IndexBuildMulti, runtime.NumCPU())
+	fmt.Printf("HNSW Graph inserts per second %0.6f (Single thread)\n\n", stats.IndexBuildSingle)
+
+	fmt.Printf("HNSW enter-point (Ep) => %d\n", h.Ep)
+	fmt.Printf("Maxlevel => %d\n\n", h.Maxlevel)
+
+	// Nodes on level 0 with no incoming links are never returned by search
+	connectivity, err := h.Connectivity(0)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stats.Orphans = len(connectivity.Orphans)
+	stats.Unreachable = len(connectivity.Unreachable)
+
+	fmt.Printf("Orphans (level 0) => %d, unreachable %d, components %d\n", stats.Orphans, stats.Unreachable, connectivity.Components)
+
+	if *reconnect {
+
+		start := time.Now()
+
+		reconnected, err := h.Reconnect()
 
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		connectivity, err = h.Connectivity(0)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("Reconnected %d nodes in %0.6f (secs), orphans => %d, unreachable %d\n", reconnected, time.Since(start).Seconds(), len(connectivity.Orphans), len(connectivity.Unreachable))
+
 	}
 
 	fmt.Println()
 
-	close(jobs)
-
-	h.Wg.Wait()
-	close(resultChan)
+	stats.Graph = h.Stats()
 
-	end := time.Since(start)
+	fmt.Println("HNSW Stats:")
+	fmt.Print(stats.Graph)
+	fmt.Println()
 
-	numQ := vecNum
+	stats.VectorBytes, stats.CodeBytes = h.VectorMemory()
 
-	stats.IndexBuildSecs = end.Seconds()
-	stats.IndexBuildMulti = float64(*numQ) / end.Seconds()
-	stats.IndexBuildSingle = float64(*numQ) / end.Seconds() / float64(runtime.NumCPU())
+	fmt.Printf("Vector memory %0.2f MB (%s)\n", float64(stats.VectorBytes)/(1<<20), h.VectorType)
 
-	fmt.Printf("HNSW Graph built in %0.6f (secs)\n", stats.IndexBuildSecs)
-	fmt.Printf("HNSW Graph inserts per second %0.6f (%d threaded)\n", stats.IndexBuildMulti, runtime.NumCPU())
-	fmt.Printf("HNSW Graph inserts per second %0.6f (Single thread)\n\n", stats.IndexBuildSingle)
+	if h.Quantization != hnsw.QuantizationNone {
+		fmt.Printf("Quantized vector memory %0.2f MB (%s), %0.1fx smaller\n", float64(stats.CodeBytes)/(1<<20), h.Quantization, float64(stats.VectorBytes)/float64(max(1, int(stats.CodeBytes))))
+	}
 
-	fmt.Printf("HNSW enter-point (Ep) => %d\n", h.Ep)
-	fmt.Printf("Maxlevel => %d\n\n", h.Maxlevel)
+	fmt.Println()
 
 	groundResults := make([][]uint32, 0)
+	var groundDists [][]float32
 	var hitSuccess int = 0
 
-	if *groundtruth == true {
+	if *groundtruth == true && *groundtruthFile != "" {
+
+		fmt.Printf("Loading Ground truth from %s. Returning (%d-NN) hits\n", *groundtruthFile, *k)
 
-		fmt.Printf("Building Ground truth (brute) search for (%d) records. Returning (%d-NN) hits\n", *numQ, *k)
+		groundResults, groundDists, err = loadGroundTruth(*groundtruthFile, numQ, *k, nodes)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println("================================")
+
+	} else if *groundtruth == true && h.VectorType != hnsw.VectorFloat32 {
+
+		// The index holds reduced precision vectors, compare against the float32 ground truth to report recall parity
+		fmt.Printf("Building float32 Ground truth (brute) search for (%d) records. Returning (%d-NN) hits\n", numQ, *k)
+
+		ids, dists := computeGroundTruth(vec, queries, *k, runtime.NumCPU())
+
+		groundResults, err = groundTruthNodes(ids, nodes)
+		groundDists = dists
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println("\n================================")
+
+	} else if *groundtruth == true {
+
+		fmt.Printf("Building Ground truth (brute) search for (%d) records. Returning (%d-NN) hits\n", numQ, *k)
 
 		start = time.Now()
 
-		bruteSearchChan, bruteSearchJobs, err := h.BruteSearchConcurrent(*numQ, *k, runtime.NumCPU())
+		bruteSearchChan, bruteSearchJobs, err := h.BruteSearchConcurrent(numQ, *k, runtime.NumCPU())
 
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		groundResults = make([][]uint32, *numQ+1)
+		groundResults = make([][]uint32, numQ+1)
+		groundDists = make([][]float32, numQ+1)
 
-		for i := 0; i < *numQ; i++ {
-			bruteSearchJobs <- hnsw.SearchQuery{Id: i, Qp: vec[i]}
+		for i := 0; i < numQ; i++ {
+			bruteSearchJobs <- hnsw.SearchQuery{Id: i, Qp: queries[i]}
 
 			if i%1000 == 0 {
 				// Clear the current line
@@ -258,33 +471,16 @@ func main() {
 		close(bruteSearchChan)
 
 		for result := range bruteSearchChan {
-
-			for i3 := *k - 1; i3 >= 0; i3-- {
-
-				if result.BestCandidates.Len() == 0 {
-					break
-				}
-
-				groundResults[result.Id] = make([]uint32, *k)
-
-				for i2 := *k - 1; i2 >= 0; i2-- {
-					if result.BestCandidates.Len() > 0 {
-						item := heap.Pop(&result.BestCandidates).(*queue.Item)
-						groundResults[result.Id][i2] = item.Node
-					}
-				}
-
-			}
-
+			groundResults[result.Id], groundDists[result.Id] = drainResults(&result.BestCandidates)
 		}
 
-		fmt.Println("\nBrute Search Stats:\n")
+		fmt.Printf("\nBrute Search Stats:\n\n")
 
 		end = time.Since(start)
 
 		stats.BruteSearchSecs = end.Seconds()
-		stats.BruteSearchMulti = float64(*numQ) / end.Seconds()
-		stats.BruteSearchSingle = float64(*numQ) / end.Seconds() / float64(runtime.NumCPU())
+		stats.BruteSearchMulti = float64(numQ) / end.Seconds()
+		stats.BruteSearchSingle = float64(numQ) / end.Seconds() / float64(runtime.NumCPU())
 
 		fmt.Printf("Brute search complete in %0.6f (secs)\n", stats.BruteSearchSecs)
 
@@ -295,6 +491,27 @@ func main() {
 
 	}
 
+	// The brute search ground truth requires the in-memory vectors, release once complete
+	if *releaseVectors {
+
+		store, err := vectors.OpenFvecsFile(*baseFile)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defer store.Close()
+
+		err = h.ReleaseVectors(newBaseFileStore(store, nodes))
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("Released in-memory vectors, reranking from %s\n", *baseFile)
+
+	}
+
 	if *hnswsearch == true {
 
 		for efSearch := 10; efSearch <= h.Efconstruction; efSearch += 10 {
@@ -306,14 +523,17 @@ func main() {
 
 			totalSearch := 0
 
-			searchChan, searchJobs, err := h.SearchConcurrent(*numQ, *k, efSearch, runtime.NumCPU())
+			metrics := recallMetrics{}
+			latencies := make([]time.Duration, 0, numQ)
+
+			searchChan, searchJobs, err := h.SearchConcurrent(numQ, *k, efSearch, runtime.NumCPU())
 
 			if err != nil {
 				log.Fatal(err)
 			}
 
-			for i := 0; i < *numQ; i++ {
-				searchJobs <- hnsw.SearchQuery{Id: i, Qp: vec[i]}
+			for i := 0; i < numQ; i++ {
+				searchJobs <- hnsw.SearchQuery{Id: i, Qp: queries[i]}
 
 				if i%1000 == 0 {
 					// Clear the current line
@@ -332,52 +552,87 @@ func main() {
 
 			for result := range searchChan {
 
-				for i3 := *k - 1; i3 >= 0; i3-- {
+				latencies = append(latencies, result.Latency)
 
-					if result.BestCandidates.Len() == 0 {
-						//fmt.Println("No matches")
-						break
-					}
+				ids, dists := drainResults(&result.BestCandidates)
+				totalSearch += len(ids)
+
+				if *groundtruth == true {
 
-					item := heap.Pop(&result.BestCandidates).(*queue.Item)
-					totalSearch++
+					var truthDists []float32
 
-					if *groundtruth == true {
+					if groundDists != nil {
+						truthDists = groundDists[result.Id]
+					}
 
-						for k := *k - 1; k >= 0; k-- {
+					metrics.add(ids, dists, groundResults[result.Id], truthDists, *k)
 
-							if item.Node == groundResults[result.Id][k] {
+					for _, id := range ids {
+						for _, truth := range groundResults[result.Id] {
+							if id == truth {
 								hitSuccess++
 							}
-
 						}
-
 					}
+
 				}
 
 			}
 
-			fmt.Println("HNSW Stats:")
-
-			h.Stats()
-
 			stats.HNSWSearchSecs = end.Seconds()
-			stats.HNSWSearchMulti = float64(*numQ) / end.Seconds()
-			stats.HNSWSearchSingle = float64(*numQ) / end.Seconds() / float64(runtime.NumCPU())
+			stats.HNSWSearchMulti = float64(numQ) / end.Seconds()
+			stats.HNSWSearchSingle = float64(numQ) / end.Seconds() / float64(runtime.NumCPU())
 
 			fmt.Printf("HNSW search complete in %0.6f (secs)\n", stats.HNSWSearchSecs)
 
 			fmt.Printf("HNSW search queries per second %0.6f (%d threaded)\n", stats.HNSWSearchMulti, runtime.NumCPU())
 			fmt.Printf("HNSW search queries per second %0.6f (Single threaded)\n", stats.HNSWSearchSingle)
 
+			sortLatencies(latencies)
+
+			stats.LatencyP50Ms = percentileMs(latencies, 50)
+			stats.LatencyP90Ms = percentileMs(latencies, 90)
+			stats.LatencyP99Ms = percentileMs(latencies, 99)
+			stats.LatencyP999Ms = percentileMs(latencies, 99.9)
+
+			fmt.Printf("HNSW search latency (ms) p50 %0.6f, p90 %0.6f, p99 %0.6f, p999 %0.6f\n", stats.LatencyP50Ms, stats.LatencyP90Ms, stats.LatencyP99Ms, stats.LatencyP999Ms)
+
 			fmt.Println("================================")
 
 			stats.GroundTruthHits = hitSuccess
-			stats.HNSWPrecision = float64(hitSuccess) / (float64(*numQ) * float64(*k))
+			stats.HNSWPrecision = float64(hitSuccess) / (float64(numQ) * float64(*k))
 
-			fmt.Printf("Total searches %d\n", stats.Size)
+			fmt.Printf("Total searches %d\n", stats.Queries)
 			fmt.Printf("Total matches from ground Truth: %d\n", stats.GroundTruthHits)
-			fmt.Printf("Average 10-NN precision: %0.6f\n", stats.HNSWPrecision)
+			fmt.Printf("Average %d-NN precision: %0.6f\n", *k, stats.HNSWPrecision)
+
+			if *groundtruth == true {
+
+				stats.Recall1 = metrics.Recall1()
+				stats.RecallK = metrics.RecallK()
+
+				if *k >= 10 {
+					stats.Recall10 = metrics.Recall10()
+				}
+
+				stats.MeanDistRatio = metrics.DistRatio()
+
+				fmt.Printf("Recall@1: %0.6f\n", stats.Recall1)
+
+				if *k >= 10 {
+					fmt.Printf("Recall@10: %0.6f\n", stats.Recall10)
+				}
+
+				// Already printed as Recall@1 or Recall@10
+				if *k != 1 && *k != 10 {
+					fmt.Printf("Recall@%d: %0.6f\n", *k, stats.RecallK)
+				}
+
+				if metrics.distRatioQueries > 0 {
+					fmt.Printf("Mean distance ratio: %0.6f\n", stats.MeanDistRatio)
+				}
+
+			}
 
 			// Optional, save our results
 			stats.DateEnd = time.Now()
@@ -416,6 +671,21 @@ func main() {
 						"HNSWPrecision",
 						"DateStart",
 						"DateEnd",
+
+						// Columns added since are appended, so existing files and readers by position keep their columns
+						"Queries",
+						"Recall1",
+						"Recall10",
+						"RecallK",
+						"MeanDistRatio",
+						"LatencyP50Ms",
+						"LatencyP90Ms",
+						"LatencyP99Ms",
+						"LatencyP999Ms",
+						"Quantization",
+						"VectorBytes",
+						"CodeBytes",
+						"VectorType",
 					}
 
 					err = csvWriter.Write(header)
@@ -460,6 +730,24 @@ func main() {
 
 					fmt.Sprintf("%s", stats.DateStart),
 					fmt.Sprintf("%s", stats.DateEnd),
+
+					fmt.Sprintf("%d", stats.Queries),
+
+					fmt.Sprintf("%0.6f", stats.Recall1),
+					fmt.Sprintf("%0.6f", stats.Recall10),
+					fmt.Sprintf("%0.6f", stats.RecallK),
+					fmt.Sprintf("%0.6f", stats.MeanDistRatio),
+
+					fmt.Sprintf("%0.6f", stats.LatencyP50Ms),
+					fmt.Sprintf("%0.6f", stats.LatencyP90Ms),
+					fmt.Sprintf("%0.6f", stats.LatencyP99Ms),
+					fmt.Sprintf("%0.6f", stats.LatencyP999Ms),
+
+					stats.Quantization,
+					fmt.Sprintf("%d", stats.VectorBytes),
+					fmt.Sprintf("%d", stats.CodeBytes),
+
+					stats.VectorType,
 				},
 				)
 
@@ -469,6 +757,17 @@ func main() {
 
 			}
 
+			// Write JSON file
+			if jsonEncoder != nil {
+
+				err := jsonEncoder.Encode(stats)
+
+				if err != nil {
+					log.Fatal("Error writing record to JSON:", err)
+				}
+
+			}
+
 		}
 	}
 
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+)
+
+// Accumulate recall and distance ratio for each query against the ground truth (ANN-Benchmarks definitions)
+type recallMetrics struct {
+	queries int
+
+	recall1  float64
+	recall10 float64
+	recallK  float64
+
+	distRatio        float64
+	distRatioQueries int
+}
+
+// Split the results into ids and distances, ordered nearest first
+func drainResults(results *queue.TopK) (ids []uint32, dists []float32) {
+
+	ids = make([]uint32, results.Len())
+	dists = make([]float32, results.Len())
+
+	for i, item := range results.Drain(nil) {
+		ids[i] = item.Node
+		dists[i] = item.Distance
+	}
+
+	return
+
+}
+
+// Fraction of the true `n` nearest neighbours found within the first `n` results
+func recallAt(ids []uint32, truth []uint32, n int) float64 {
+
+	if n > len(truth) {
+		n = len(truth)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	hits := 0
+
+	for i := 0; i < n && i < len(ids); i++ {
+		for i2 := 0; i2 < n; i2++ {
+			if ids[i] == truth[i2] {
+				hits++
+				break
+			}
+		}
+	}
+
+	return float64(hits) / float64(n)
+
+}
+
+// Add a query, results and ground truth ordered nearest first, truthDists is optional (nil if not available)
+func (m *recallMetrics) add(ids []uint32, dists []float32, truth []uint32, truthDists []float32, k int) {
+
+	m.queries++
+
+	m.recall1 += recallAt(ids, truth, 1)
+	m.recall10 += recallAt(ids, truth, 10)
+	m.recallK += recallAt(ids, truth, k)
+
+	if truthDists == nil {
+		return
+	}
+
+	// Ratio of the (non-squared) L2 distance at each rank compared with the true neighbour at the same rank
+	ratio := 0.0
+	n := 0
+
+	for i := 0; i < len(dists) && i < len(truthDists) && i < k; i++ {
+
+		if truthDists[i] == 0 {
+			if dists[i] == 0 {
+				ratio += 1
+				n++
+			}
+			continue
+		}
+
+		ratio += math.Sqrt(float64(dists[i])) / math.Sqrt(float64(truthDists[i]))
+		n++
+
+	}
+
+	if n > 0 {
+		m.distRatio += ratio / float64(n)
+		m.distRatioQueries++
+	}
+
+}
+
+func (m *recallMetrics) Recall1() float64 {
+	return m.recall1 / float64(max(1, m.queries))
+}
+
+func (m *recallMetrics) Recall10() float64 {
+	return m.recall10 / float64(max(1, m.queries))
+}
+
+func (m *recallMetrics) RecallK() float64 {
+	return m.recallK / float64(max(1, m.queries))
+}
+
+func (m *recallMetrics) DistRatio() float64 {
+	return m.distRatio / float64(max(1, m.distRatioQueries))
+}
+
+// Latency percentile (0-100) in milliseconds, latencies must be sorted
+func percentileMs(latencies []time.Duration, p float64) float64 {
+
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	// Nearest-rank method, rounding error is removed first so e.g p99.9 of 1000 latencies is rank 999, not 1000
+	rank := int(math.Ceil(p*float64(len(latencies))/100 - 1e-9))
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return float64(latencies[rank-1]) / float64(time.Millisecond)
+
+}
+
+func sortLatencies(latencies []time.Duration) {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
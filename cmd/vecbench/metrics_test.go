@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RecallAt(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		ids      []uint32
+		truth    []uint32
+		n        int
+		expected float64
+	}{
+		{"exact", []uint32{1, 2, 3}, []uint32{1, 2, 3}, 3, 1},
+		{"order within n is ignored", []uint32{3, 1, 2}, []uint32{1, 2, 3}, 3, 1},
+		{"half", []uint32{1, 5, 2, 6}, []uint32{1, 2, 3, 4}, 4, 0.5},
+		{"nearest only", []uint32{2, 1}, []uint32{1, 2}, 1, 0},
+		{"hits beyond n are ignored", []uint32{5, 6, 1}, []uint32{1, 2, 3}, 2, 0},
+		{"fewer results than n", []uint32{1}, []uint32{1, 2}, 2, 0.5},
+		{"n capped to the truth", []uint32{1, 2}, []uint32{1, 2}, 10, 1},
+		{"no truth", []uint32{1}, []uint32{}, 1, 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, recallAt(test.ids, test.truth, test.n), test.name)
+	}
+
+}
+
+func Test_RecallMetrics(t *testing.T) {
+
+	m := recallMetrics{}
+
+	truth := []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	truthDists := []float32{1, 4, 9, 16}
+
+	// Exact results, distances at each rank match
+	m.add(truth, []float32{1, 4, 9, 16}, truth, truthDists, 12)
+
+	// Nearest missed, 6 of the first 10 and 8 of the 12 found, each distance twice the true (squared distances 4x)
+	m.add([]uint32{20, 2, 3, 4, 5, 6, 7, 21, 22, 23, 8, 9}, []float32{4, 16, 36, 64}, truth, truthDists, 12)
+
+	// No distances, not part of the ratio
+	m.add(truth, nil, truth, nil, 12)
+
+	assert.InDelta(t, 2.0/3, m.Recall1(), 1e-9)
+	assert.InDelta(t, (1+0.6+1)/3, m.Recall10(), 1e-9)
+	assert.InDelta(t, (1+8.0/12+1)/3, m.RecallK(), 1e-9)
+	assert.InDelta(t, 1.5, m.DistRatio(), 1e-9)
+
+	// A true distance of zero only counts an exact match
+	m = recallMetrics{}
+	m.add([]uint32{1, 2}, []float32{0, 1}, []uint32{1, 2}, []float32{0, 1}, 2)
+	m.add([]uint32{3, 2}, []float32{1, 1}, []uint32{1, 2}, []float32{0, 1}, 2)
+
+	assert.InDelta(t, 1.0, m.DistRatio(), 1e-9)
+
+	// No queries
+	assert.Equal(t, 0.0, (&recallMetrics{}).Recall1())
+	assert.Equal(t, 0.0, (&recallMetrics{}).DistRatio())
+
+}
+
+func Test_PercentileMs(t *testing.T) {
+
+	latencies := make([]time.Duration, 1000)
+
+	for i := range latencies {
+		latencies[i] = time.Duration(1000-i) * time.Millisecond
+	}
+
+	sortLatencies(latencies)
+
+	tests := []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 1},
+		{50, 500},
+		{90, 900},
+		{99, 990},
+		{99.9, 999},
+		{100, 1000},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, percentileMs(latencies, test.p), test.p)
+	}
+
+	// Nearest rank of a small sample
+	assert.Equal(t, 2.0, percentileMs([]time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}, 50))
+	assert.Equal(t, 0.0, percentileMs(nil, 50))
+
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	vectordbv1 "github.com/aws-samples/gofast-hnsw/api/vectordb/v1"
+	"github.com/aws-samples/gofast-hnsw/vectordb/collection"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// gRPC API over the same index as the HTTP/JSON API, see api/vectordb/v1/vectordb.proto
+type grpcService struct {
+	vectordbv1.UnimplementedVectorDBServer
+
+	s *server
+}
+
+func (s *server) grpcServer() *grpc.Server {
+
+	g := grpc.NewServer(grpc.MaxRecvMsgSize(maxRequestBytes))
+	vectordbv1.RegisterVectorDBServer(g, &grpcService{s: s})
+
+	return g
+
+}
+
+func (g *grpcService) CreateIndex(ctx context.Context, req *vectordbv1.IndexConfig) (*vectordbv1.IndexConfig, error) {
+
+	x, err := g.s.create(collection.Config{
+		Dim:            int(req.Dim),
+		M:              int(req.M),
+		Mmax:           int(req.Mmax),
+		Mmax0:          int(req.Mmax0),
+		EfConstruction: int(req.EfConstruction),
+		Heuristic:      req.Heuristic,
+		VectorType:     req.VectorType,
+		MaxMemory:      int64(req.MaxMemory),
+	})
+
+	if errors.Is(err, errIndexExists) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	config := x.Config()
+
+	return &vectordbv1.IndexConfig{
+		Dim:            uint32(config.Dim),
+		M:              uint32(config.M),
+		Mmax:           uint32(config.Mmax),
+		Mmax0:          uint32(config.Mmax0),
+		EfConstruction: uint32(config.EfConstruction),
+		Heuristic:      config.Heuristic,
+		VectorType:     config.VectorType,
+		MaxMemory:      uint64(config.MaxMemory),
+	}, nil
+
+}
+
+func (g *grpcService) Insert(ctx context.Context, req *vectordbv1.InsertRequest) (*vectordbv1.InsertResponse, error) {
+
+	x, err := g.index()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertBatch(x, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &vectordbv1.InsertResponse{Inserted: uint64(len(req.Vectors))}, nil
+
+}
+
+func (g *grpcService) BulkInsert(stream vectordbv1.VectorDB_BulkInsertServer) error {
+
+	x, err := g.index()
+
+	if err != nil {
+		return err
+	}
+
+	inserted := uint64(0)
+
+	for {
+
+		req, err := stream.Recv()
+
+		if err == io.EOF {
+			return stream.SendAndClose(&vectordbv1.InsertResponse{Inserted: inserted})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = insertBatch(x, req)
+
+		if err != nil {
+			return err
+		}
+
+		inserted += uint64(len(req.Vectors))
+
+	}
+
+}
+
+func (g *grpcService) Search(ctx context.Context, req *vectordbv1.SearchRequest) (*vectordbv1.SearchResponse, error) {
+
+	x, err := g.index()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return search(x, req)
+
+}
+
+func (g *grpcService) BatchSearch(stream vectordbv1.VectorDB_BatchSearchServer) error {
+
+	x, err := g.index()
+
+	if err != nil {
+		return err
+	}
+
+	for {
+
+		req, err := stream.Recv()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		resp, err := search(x, req)
+
+		if err != nil {
+			return err
+		}
+
+		err = stream.Send(resp)
+
+		if err != nil {
+			return err
+		}
+
+	}
+
+}
+
+func (g *grpcService) Delete(ctx context.Context, req *vectordbv1.DeleteRequest) (*vectordbv1.DeleteResponse, error) {
+
+	x, err := g.index()
+
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := x.Delete(req.Ids)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &vectordbv1.DeleteResponse{Deleted: uint64(deleted)}, nil
+
+}
+
+func (g *grpcService) GetStats(ctx context.Context, req *vectordbv1.GetStatsRequest) (*vectordbv1.Stats, error) {
+
+	x, err := g.index()
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats := x.Stats()
+
+	resp := &vectordbv1.Stats{
+		Dim:            uint32(stats.Dim),
+		VectorType:     stats.VectorType,
+		M:              uint32(stats.M),
+		Mmax:           uint32(stats.Mmax),
+		Mmax0:          uint32(stats.Mmax0),
+		EfConstruction: uint32(stats.EfConstruction),
+		Ep:             stats.Ep,
+		MaxLevel:       uint32(stats.MaxLevel),
+		Heuristic:      stats.Heuristic,
+		Ml:             stats.Ml,
+		Nodes:          uint64(stats.Nodes),
+		Vectors:        uint64(stats.Vectors),
+		Deleted:        uint64(stats.Deleted),
+		Memory:         uint64(stats.Memory),
+		Seq:            stats.Seq,
+		SnapshotSeq:    stats.SnapshotSeq,
+		SnapshotTime:   timestamppb.New(stats.SnapshotTime),
+	}
+
+	for _, level := range stats.Levels {
+
+		resp.Levels = append(resp.Levels, &vectordbv1.LevelStats{
+			Level:          uint32(level.Level),
+			Nodes:          uint64(level.Nodes),
+			Connections:    uint64(level.Connections),
+			AvgConnections: uint64(level.AvgConnections),
+		})
+
+	}
+
+	return resp, nil
+
+}
+
+// Private functions
+
+// The index, or a FailedPrecondition error if it has not been created
+func (g *grpcService) index() (*collection.Collection, error) {
+
+	x := g.s.current()
+
+	if x == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no index, create one with CreateIndex")
+	}
+
+	return x, nil
+
+}
+
+func insertBatch(x *collection.Collection, req *vectordbv1.InsertRequest) error {
+
+	items := make([]collection.Item, len(req.Vectors))
+
+	for i, v := range req.Vectors {
+		items[i] = collection.Item{Id: v.Id, Vector: v.Values}
+	}
+
+	err := x.Insert(items, req.Upsert)
+
+	if errors.Is(err, collection.ErrExists) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	if errors.Is(err, collection.ErrMemoryLimit) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+
+}
+
+func search(x *collection.Collection, req *vectordbv1.SearchRequest) (*vectordbv1.SearchResponse, error) {
+
+	k, efSearch := int(req.K), int(req.EfSearch)
+
+	if k == 0 {
+		k = defaultK
+	}
+
+	if efSearch == 0 {
+		efSearch = defaultEfSearch
+	}
+
+	start := time.Now()
+
+	results, err := x.Search(req.Vector, k, efSearch)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &vectordbv1.SearchResponse{Results: make([]*vectordbv1.SearchResult, len(results))}
+
+	for i, result := range results {
+		resp.Results[i] = &vectordbv1.SearchResult{Id: result.Id, Distance: result.Distance}
+	}
+
+	resp.LatencyUs = time.Since(start).Microseconds()
+
+	return resp, nil
+
+}
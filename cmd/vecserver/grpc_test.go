@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	vectordbv1 "github.com/aws-samples/gofast-hnsw/api/vectordb/v1"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Serve `s` over an in-memory connection, closed with the test
+func newGRPCClient(t *testing.T, s *server) vectordbv1.VectorDBClient {
+
+	listener := bufconn.Listen(1 << 20)
+
+	g := s.grpcServer()
+	go g.Serve(listener)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+
+	t.Cleanup(func() { conn.Close() })
+
+	return vectordbv1.NewVectorDBClient(conn)
+
+}
+
+func Test_GRPC(t *testing.T) {
+
+	ctx := context.Background()
+
+	s, err := newServer(t.TempDir())
+	assert.Nil(t, err)
+
+	client := newGRPCClient(t, s)
+
+	// No index yet
+	_, err = client.Search(ctx, &vectordbv1.SearchRequest{Vector: []float32{1}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	config, err := client.CreateIndex(ctx, &vectordbv1.IndexConfig{Dim: 16, M: 8, EfConstruction: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(16), config.Mmax0)
+
+	_, err = client.CreateIndex(ctx, &vectordbv1.IndexConfig{Dim: 16})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	vecs, _ := vectors.GenerateRandomVectors(500, 16)
+
+	// Unary insert of the first 100, the rest streamed in batches of 100
+	batch := func(from int, to int) *vectordbv1.InsertRequest {
+
+		req := &vectordbv1.InsertRequest{}
+
+		for i := from; i < to; i++ {
+			req.Vectors = append(req.Vectors, &vectordbv1.Vector{Id: uint64(1000 + i), Values: vecs[i]})
+		}
+
+		return req
+
+	}
+
+	inserted, err := client.Insert(ctx, batch(0, 100))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), inserted.Inserted)
+
+	_, err = client.Insert(ctx, batch(0, 1))
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.Insert(ctx, &vectordbv1.InsertRequest{Vectors: []*vectordbv1.Vector{{Id: 1, Values: []float32{1}}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	bulk, err := client.BulkInsert(ctx)
+	assert.Nil(t, err)
+
+	for i := 100; i < len(vecs); i += 100 {
+		assert.Nil(t, bulk.Send(batch(i, i+100)))
+	}
+
+	inserted, err = bulk.CloseAndRecv()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(400), inserted.Inserted)
+
+	resp, err := client.Search(ctx, &vectordbv1.SearchRequest{Vector: vecs[10], K: 5, EfSearch: 50})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(resp.Results))
+	assert.Equal(t, uint64(1010), resp.Results[0].Id)
+
+	// Default k
+	resp, err = client.Search(ctx, &vectordbv1.SearchRequest{Vector: vecs[10]})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultK, len(resp.Results))
+
+	deleted, err := client.Delete(ctx, &vectordbv1.DeleteRequest{Ids: []uint64{1020, 1, 1030}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), deleted.Deleted)
+
+	// Responses in request order, deleted ids are not returned
+	batchSearch, err := client.BatchSearch(ctx)
+	assert.Nil(t, err)
+
+	queries := []int{20, 30, 40, 450}
+
+	go func() {
+
+		for _, i := range queries {
+			batchSearch.Send(&vectordbv1.SearchRequest{Vector: vecs[i], K: 3, EfSearch: 50})
+		}
+
+		batchSearch.CloseSend()
+
+	}()
+
+	for _, i := range queries {
+
+		resp, err := batchSearch.Recv()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(resp.Results))
+
+		if i == 20 || i == 30 {
+
+			for _, result := range resp.Results {
+				assert.NotEqual(t, uint64(1000+i), result.Id)
+			}
+
+		} else {
+			assert.Equal(t, uint64(1000+i), resp.Results[0].Id)
+		}
+
+	}
+
+	_, err = batchSearch.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	stats, err := client.GetStats(ctx, &vectordbv1.GetStatsRequest{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, uint32(16), stats.Dim)
+	assert.Equal(t, uint64(501), stats.Nodes)
+	assert.Equal(t, uint64(498), stats.Vectors)
+	assert.Equal(t, uint64(2), stats.Deleted)
+	assert.Equal(t, int(stats.MaxLevel)+1, len(stats.Levels))
+
+	// Shared with the HTTP API
+	assert.Equal(t, 498, s.index.Len())
+
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+
+	listen := flag.String("listen", ":8080", "Address to listen on for HTTP requests")
+	grpcListen := flag.String("grpc-listen", ":9090", "Address to listen on for gRPC requests (empty to disable)")
+	dataDir := flag.String("data-dir", "data", "Directory holding the index, loaded on startup if present")
+	snapshotInterval := flag.Duration("snapshot-interval", 5*time.Minute, "Interval between snapshots of the index to -data-dir, if changed (0 to snapshot only on shutdown)")
+
+	flag.Parse()
+
+	s, err := newServer(*dataDir)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if s.index != nil {
+		log.Printf("Loaded index from %s, %d vectors", *dataDir, s.index.Len())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *snapshotInterval > 0 {
+		go runSnapshots(ctx, s, *snapshotInterval)
+	}
+
+	httpServer := &http.Server{Addr: *listen, Handler: s.handler()}
+
+	go func() {
+
+		log.Printf("Listening on %s", *listen)
+
+		err := httpServer.ListenAndServe()
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+
+	}()
+
+	grpcServer := s.grpcServer()
+
+	if *grpcListen != "" {
+
+		listener, err := net.Listen("tcp", *grpcListen)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		go func() {
+
+			log.Printf("Listening for gRPC on %s", *grpcListen)
+
+			err := grpcServer.Serve(listener)
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+		}()
+
+	}
+
+	<-ctx.Done()
+
+	log.Println("Shutting down")
+
+	grpcServer.GracefulStop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err = httpServer.Shutdown(shutdownCtx)
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = s.snapshot()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+}
+
+// Snapshot the index every `interval` until ctx is done
+func runSnapshots(ctx context.Context, s *server, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+
+		saved, err := s.snapshot()
+
+		if err != nil {
+			log.Printf("Snapshot failed: %v", err)
+			continue
+		}
+
+		if saved {
+			log.Printf("Snapshot complete in %s", time.Since(start))
+		}
+
+	}
+
+}
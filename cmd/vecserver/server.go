@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/collection"
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/metrics"
+)
+
+// Maximum size of a request body
+const maxRequestBytes = 64 << 20
+
+// Defaults for search, ef is raised to k if lower
+const (
+	defaultK        = 10
+	defaultEfSearch = 100
+)
+
+var errIndexExists = errors.New("index already exists")
+
+// HTTP/JSON API over a single index held in the data directory
+type server struct {
+	dataDir string
+	metrics *metrics.Registry
+
+	mutex sync.RWMutex
+	index *collection.Collection // nil until created with POST /index
+}
+
+type insertRequest struct {
+	Vectors []collection.Item `json:"vectors"`
+}
+
+type deleteRequest struct {
+	Ids []uint64 `json:"ids"`
+}
+
+type searchRequest struct {
+	Vector   []float32 `json:"vector"`
+	K        int       `json:"k"`
+	EfSearch int       `json:"ef_search"`
+	Explain  bool      `json:"explain"` // Return a trace of the graph traversal
+}
+
+type searchResponse struct {
+	Results   []collection.Result `json:"results"`
+	LatencyUs int64               `json:"latency_us"`
+	Trace     *hnsw.Trace         `json:"trace,omitempty"`
+}
+
+// Open the index held in `dataDir`, if any
+func newServer(dataDir string) (s *server, err error) {
+
+	s = &server{dataDir: dataDir, metrics: metrics.NewRegistry()}
+
+	x, err := collection.Open(dataDir)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.index = s.instrument(x)
+
+	return s, nil
+
+}
+
+func (s *server) handler() http.Handler {
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/index", s.handleCreate)
+	mux.HandleFunc("/insert", s.handleInsert(false))
+	mux.HandleFunc("/upsert", s.handleInsert(true))
+	mux.HandleFunc("/delete", s.handleDelete)
+	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/stats", s.handleStats)
+	mux.Handle("/metrics", s.metrics)
+
+	return mux
+
+}
+
+// Create the index, errIndexExists if it was already created
+func (s *server) create(config collection.Config) (x *collection.Collection, err error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.index != nil {
+		return nil, errIndexExists
+	}
+
+	x, err = collection.Create(s.dataDir, config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.index = s.instrument(x)
+
+	return x, nil
+
+}
+
+// Snapshot the index if it changed since the last snapshot, returns true if saved
+func (s *server) snapshot() (saved bool, err error) {
+
+	x := s.current()
+
+	if x == nil || !x.Changed() {
+		return false, nil
+	}
+
+	return true, x.Snapshot()
+
+}
+
+// POST /index, create the index
+func (s *server) handleCreate(w http.ResponseWriter, r *http.Request) {
+
+	config := collection.Config{}
+
+	if !decodeRequest(w, r, http.MethodPost, &config) {
+		return
+	}
+
+	x, err := s.create(config)
+
+	if errors.Is(err, errIndexExists) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, x.Config())
+
+}
+
+// POST /insert and /upsert, insert a batch of vectors
+func (s *server) handleInsert(upsert bool) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		req := insertRequest{}
+
+		if !decodeRequest(w, r, http.MethodPost, &req) {
+			return
+		}
+
+		x := s.indexOrError(w)
+
+		if x == nil {
+			return
+		}
+
+		err := x.Insert(req.Vectors, upsert)
+
+		if errors.Is(err, collection.ErrExists) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+
+		if errors.Is(err, collection.ErrMemoryLimit) {
+			writeError(w, http.StatusInsufficientStorage, err)
+			return
+		}
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]int{"inserted": len(req.Vectors)})
+
+	}
+
+}
+
+// POST /delete, delete vectors by id
+func (s *server) handleDelete(w http.ResponseWriter, r *http.Request) {
+
+	req := deleteRequest{}
+
+	if !decodeRequest(w, r, http.MethodPost, &req) {
+		return
+	}
+
+	x := s.indexOrError(w)
+
+	if x == nil {
+		return
+	}
+
+	deleted, err := x.Delete(req.Ids)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{"deleted": deleted})
+
+}
+
+// POST /search, k-NN search
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+
+	req := searchRequest{K: defaultK, EfSearch: defaultEfSearch}
+
+	if !decodeRequest(w, r, http.MethodPost, &req) {
+		return
+	}
+
+	if req.K < 1 || req.EfSearch < 1 {
+		writeError(w, http.StatusBadRequest, errors.New("k and ef_search must be at least 1"))
+		return
+	}
+
+	x := s.indexOrError(w)
+
+	if x == nil {
+		return
+	}
+
+	start := time.Now()
+
+	var results []collection.Result
+	var trace *hnsw.Trace
+	var err error
+
+	if req.Explain {
+		results, trace, err = x.SearchTrace(req.Vector, req.K, req.EfSearch)
+	} else {
+		results, err = x.Search(req.Vector, req.K, req.EfSearch)
+	}
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, searchResponse{Results: results, LatencyUs: time.Since(start).Microseconds(), Trace: trace})
+
+}
+
+// GET /stats, index parameters and the connections of each level
+func (s *server) handleStats(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed, use GET", r.Method))
+		return
+	}
+
+	x := s.indexOrError(w)
+
+	if x == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, x.Stats())
+
+}
+
+// Private functions
+
+// Measure inserts and searches of `x` on /metrics, before it is in use
+func (s *server) instrument(x *collection.Collection) *collection.Collection {
+
+	x.Index().SetMetrics(metrics.NewIndex(s.metrics, x.Index(), nil))
+
+	return x
+
+}
+
+func (s *server) current() *collection.Collection {
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.index
+
+}
+
+// The index, or writes an error if it has not been created
+func (s *server) indexOrError(w http.ResponseWriter) *collection.Collection {
+
+	x := s.current()
+
+	if x == nil {
+		writeError(w, http.StatusNotFound, errors.New("no index, create one with POST /index"))
+	}
+
+	return x
+
+}
+
+// Decode the JSON body of a `method` request into `v`, writes an error and returns false on failure
+func decodeRequest(w http.ResponseWriter, r *http.Request, method string, v any) bool {
+
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed, use %s", r.Method, method))
+		return false
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+
+	return true
+
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(v)
+
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/collection"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Send `body` as JSON, decoding the response into `v` if not nil
+func request(t *testing.T, ts *httptest.Server, method string, path string, body any, v any) int {
+
+	buf, err := json.Marshal(body)
+	assert.Nil(t, err)
+
+	req, err := http.NewRequest(method, ts.URL+path, bytes.NewReader(buf))
+	assert.Nil(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+
+	defer resp.Body.Close()
+
+	if v != nil {
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+
+	return resp.StatusCode
+
+}
+
+func Test_Server(t *testing.T) {
+
+	dataDir := t.TempDir()
+
+	s, err := newServer(dataDir)
+	assert.Nil(t, err)
+
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	// No index yet
+	assert.Equal(t, http.StatusNotFound, request(t, ts, http.MethodPost, "/search", searchRequest{Vector: []float32{1}, K: 1, EfSearch: 1}, nil))
+
+	assert.Equal(t, http.StatusCreated, request(t, ts, http.MethodPost, "/index", collection.Config{Dim: 16, M: 8, EfConstruction: 100}, nil))
+	assert.Equal(t, http.StatusConflict, request(t, ts, http.MethodPost, "/index", collection.Config{Dim: 16}, nil))
+
+	vecs, _ := vectors.GenerateRandomVectors(500, 16)
+
+	items := make([]collection.Item, len(vecs))
+
+	for i := range vecs {
+		items[i] = collection.Item{Id: uint64(1000 + i), Vector: vecs[i]}
+	}
+
+	assert.Equal(t, http.StatusOK, request(t, ts, http.MethodPost, "/insert", insertRequest{Vectors: items}, nil))
+
+	// Existing ids require upsert, wrong dimensions are rejected
+	assert.Equal(t, http.StatusConflict, request(t, ts, http.MethodPost, "/insert", insertRequest{Vectors: items[:1]}, nil))
+	assert.Equal(t, http.StatusBadRequest, request(t, ts, http.MethodPost, "/insert", insertRequest{Vectors: []collection.Item{{Id: 1, Vector: []float32{1}}}}, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, request(t, ts, http.MethodGet, "/insert", nil, nil))
+
+	search := func(q []float32) searchResponse {
+		resp := searchResponse{}
+		assert.Equal(t, http.StatusOK, request(t, ts, http.MethodPost, "/search", searchRequest{Vector: q, K: 5, EfSearch: 50}, &resp))
+		return resp
+	}
+
+	resp := search(vecs[10])
+	assert.Equal(t, 5, len(resp.Results))
+	assert.Equal(t, uint64(1010), resp.Results[0].Id)
+	assert.Nil(t, resp.Trace)
+
+	// The same results, with the trace of the search
+	explained := searchResponse{}
+	assert.Equal(t, http.StatusOK, request(t, ts, http.MethodPost, "/search", searchRequest{Vector: vecs[10], K: 5, EfSearch: 50, Explain: true}, &explained))
+	assert.Equal(t, resp.Results, explained.Results)
+	assert.Greater(t, len(explained.Trace.Expansions), 0)
+	assert.GreaterOrEqual(t, explained.Trace.Distances, explained.Trace.Visited)
+
+	// Upsert moves id 1010 to the vector of 1020
+	assert.Equal(t, http.StatusOK, request(t, ts, http.MethodPost, "/upsert", insertRequest{Vectors: []collection.Item{{Id: 1010, Vector: vecs[20]}}}, nil))
+
+	resp = search(vecs[20])
+	assert.ElementsMatch(t, []uint64{1010, 1020}, []uint64{resp.Results[0].Id, resp.Results[1].Id})
+
+	deleted := map[string]int{}
+	assert.Equal(t, http.StatusOK, request(t, ts, http.MethodPost, "/delete", deleteRequest{Ids: []uint64{1020, 1, 1030}}, &deleted))
+	assert.Equal(t, 2, deleted["deleted"])
+
+	resp = search(vecs[30])
+
+	for _, result := range resp.Results {
+		assert.NotEqual(t, uint64(1030), result.Id)
+	}
+
+	stats := collection.Stats{}
+	assert.Equal(t, http.StatusOK, request(t, ts, http.MethodGet, "/stats", nil, &stats))
+
+	assert.Equal(t, 16, stats.Dim)
+	assert.Equal(t, 8, stats.M)
+	assert.Equal(t, 502, stats.Nodes)
+	assert.Equal(t, 498, stats.Vectors)
+	assert.Equal(t, 3, stats.Deleted)
+	assert.Equal(t, stats.MaxLevel+1, len(stats.Levels))
+
+	total := 0
+
+	for _, level := range stats.Levels {
+		total += level.Nodes
+	}
+
+	assert.Equal(t, stats.Nodes, total)
+	assert.Equal(t, stats.Nodes, stats.Graph.Levels[0].Nodes)
+	assert.Equal(t, 3, stats.Graph.Deleted)
+
+	// Searches so far are measured
+	metricsResp, err := http.Get(ts.URL + "/metrics")
+	assert.Nil(t, err)
+
+	body, err := io.ReadAll(metricsResp.Body)
+	metricsResp.Body.Close()
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, metricsResp.StatusCode)
+	assert.Contains(t, string(body), "hnsw_nodes 502\n")
+	assert.Contains(t, string(body), "hnsw_inserts_total 501\n")
+	assert.Contains(t, string(body), "# TYPE hnsw_search_duration_seconds histogram")
+
+	// Reopened from the snapshot
+	saved, err := s.snapshot()
+	assert.Nil(t, err)
+	assert.True(t, saved)
+
+	saved, err = s.snapshot()
+	assert.Nil(t, err)
+	assert.False(t, saved)
+
+	s2, err := newServer(dataDir)
+	assert.Nil(t, err)
+
+	ts2 := httptest.NewServer(s2.handler())
+	defer ts2.Close()
+
+	resp2 := searchResponse{}
+	assert.Equal(t, http.StatusOK, request(t, ts2, http.MethodPost, "/search", searchRequest{Vector: vecs[30], K: 5, EfSearch: 50}, &resp2))
+	assert.Equal(t, resp.Results, resp2.Results)
+
+	assert.Equal(t, 498, s2.index.Len())
+
+}
@@ -5,13 +5,20 @@ go 1.20
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/stretchr/testify v1.8.4
-	github.com/willf/bitset v1.1.11
 )
 
-require github.com/klauspost/cpuid/v2 v2.2.5
+require (
+	github.com/klauspost/cpuid/v2 v2.2.5
+	google.golang.org/grpc v1.62.1
+	google.golang.org/protobuf v1.33.0
+)
 
 require (
+	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.11.0 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240123012728-ef4313101c80 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
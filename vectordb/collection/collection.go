@@ -0,0 +1,667 @@
+package collection
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+)
+
+// Files written to the collection directory. The index and labels of each snapshot are written to their own directory,
+// named snapshotPrefix*, and currentLink is replaced to point at it, so a crash leaves either snapshot whole
+const (
+	configFile     = "config.json"
+	indexFile      = "index.hnsw" // Written by hnsw Save, with index.hnsw.meta
+	labelsFile     = "labels.gob"
+	currentLink    = "current"
+	snapshotPrefix = "snapshot-"
+)
+
+var (
+	ErrExists      = errors.New("id already exists, use upsert to replace it")
+	ErrMemoryLimit = errors.New("collection memory limit reached")
+	ErrClosed      = errors.New("collection is closed")
+)
+
+// Parameters of the collection, fixed when it is created
+type Config struct {
+	Dim            int    `json:"dim"`
+	M              int    `json:"m"`
+	Mmax           int    `json:"mmax"`
+	Mmax0          int    `json:"mmax0"`
+	EfConstruction int    `json:"ef_construction"`
+	Heuristic      *bool  `json:"heuristic,omitempty"` // Default true
+	VectorType     string `json:"vector_type,omitempty"`
+
+	SnapshotInterval time.Duration `json:"snapshot_interval,omitempty"` // Between snapshots by the Manager if changed, 0 to snapshot only on Close (nanoseconds in JSON)
+	MaxMemory        int64         `json:"max_memory,omitempty"`        // Bytes, inserts beyond it fail with ErrMemoryLimit (0 for no limit)
+}
+
+// A vector and the caller's id for it
+type Item struct {
+	Id     uint64    `json:"id"`
+	Vector []float32 `json:"vector"`
+}
+
+type Result struct {
+	Id       uint64  `json:"id"`
+	Distance float32 `json:"distance"`
+}
+
+// An HNSW index addressed by the caller's ids, node ids are assigned by the index in insert order. A collection is held
+// in its own directory, created with Create and reopened with Open
+type Collection struct {
+	config Config
+	dir    string
+
+	h *hnsw.HNSW
+
+	// Shared worker pools set by the Manager, batches run on the calling goroutine if nil
+	insertPool *Pool
+	searchPool *Pool
+
+	// Held shared by inserts and deletes, exclusively while a snapshot of the graph and labels is taken
+	writeGate sync.RWMutex
+
+	labelsMutex sync.RWMutex
+	nodes       map[uint64]uint32 // Caller id to node, 0 while the insert is in progress
+	labels      map[uint32]uint64 // Node to caller id
+
+	deletes atomic.Uint64 // Number of deletes, with Seq used to detect changes since the last snapshot
+
+	memory      atomic.Int64 // Measured on open and each snapshot, estimated for inserts in between
+	vectorBytes int64        // Estimated memory of each insert
+
+	snapshotMutex   sync.Mutex // Held while a snapshot is written
+	snapshotSeq     uint64
+	snapshotDeletes uint64
+	snapshotTime    time.Time
+	closed          bool // Set by close, no further snapshots are written
+}
+
+// Create a new collection in `dir`, saved immediately so it is reopened with Open
+func Create(dir string, config Config) (c *Collection, err error) {
+
+	if config.Dim <= 0 {
+		return nil, errors.New("dim must be greater than 0")
+	}
+
+	if config.M == 0 {
+		config.M = hnsw.M
+	}
+
+	if config.Mmax == 0 {
+		config.Mmax = config.M
+	}
+
+	if config.Mmax0 == 0 {
+		config.Mmax0 = config.M * 2
+	}
+
+	if config.EfConstruction == 0 {
+		config.EfConstruction = hnsw.Efconstruction
+	}
+
+	if config.M < 2 || config.Mmax < 1 || config.Mmax0 < 1 || config.EfConstruction < 1 {
+		return nil, errors.New("m must be at least 2, mmax, mmax0 and ef_construction at least 1")
+	}
+
+	if config.SnapshotInterval < 0 || config.MaxMemory < 0 {
+		return nil, errors.New("snapshot_interval and max_memory cannot be negative")
+	}
+
+	vectorType, err := parseVectorType(config.VectorType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := hnsw.New(config.M, config.Mmax, config.Mmax0, config.EfConstruction, config.Dim, hnsw.WithVectorType(vectorType))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Heuristic != nil {
+		h.Heuristic = *config.Heuristic
+	}
+
+	c = newCollection(dir, config, &h)
+
+	err = os.MkdirAll(dir, 0755)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := json.MarshalIndent(config, "", "  ")
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, configFile), buf, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Snapshot()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+
+}
+
+// Open the collection saved in `dir`, the error wraps os.ErrNotExist if the directory holds no collection
+func Open(dir string) (c *Collection, err error) {
+
+	buf, err := os.ReadFile(filepath.Join(dir, configFile))
+
+	if err != nil {
+		return nil, err
+	}
+
+	config := Config{}
+
+	err = json.Unmarshal(buf, &config)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configFile, err)
+	}
+
+	// Collections saved before snapshot directories hold the files in `dir`
+	snapshot := filepath.Join(dir, currentLink)
+
+	if _, err := os.Stat(snapshot); err != nil {
+		snapshot = dir
+	}
+
+	h, err := hnsw.Load(filepath.Join(snapshot, indexFile))
+
+	if err != nil {
+		return nil, err
+	}
+
+	c = newCollection(dir, config, &h)
+
+	file, err := os.Open(filepath.Join(snapshot, labelsFile))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	err = gob.NewDecoder(file).Decode(&c.nodes)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", labelsFile, err)
+	}
+
+	for label, node := range c.nodes {
+		c.labels[node] = label
+	}
+
+	c.snapshotSeq = h.Seq()
+	c.snapshotTime = time.Now()
+
+	return c, nil
+
+}
+
+func newCollection(dir string, config Config, h *hnsw.HNSW) *Collection {
+
+	c := &Collection{
+		config: config,
+		dir:    dir,
+		h:      h,
+		nodes:  make(map[uint64]uint32),
+		labels: make(map[uint32]uint64),
+	}
+
+	// The node, its vector, level 0 links and the list of upper levels
+	elementBytes := int64(4)
+
+	if h.VectorType != hnsw.VectorFloat32 {
+		elementBytes = 2
+	}
+
+	c.vectorBytes = int64(unsafe.Sizeof(hnsw.Node{})) + int64(config.Dim)*elementBytes + int64(config.Mmax0+1)*4 + int64(config.M+1)*int64(unsafe.Sizeof([]uint32{}))
+
+	c.memory.Store(h.Memory())
+
+	return c
+
+}
+
+// Parameters of the collection
+func (c *Collection) Config() Config {
+	return c.config
+}
+
+// Directory holding the collection
+func (c *Collection) Dir() string {
+	return c.dir
+}
+
+// The HNSW index, e.g to set metrics. Inserts and deletes must be made through the collection to keep the ids
+func (c *Collection) Index() *hnsw.HNSW {
+	return c.h
+}
+
+// Insert a batch of vectors, an existing id is an error unless `upsert` is set, the previous vector is then deleted.
+// Vectors are inserted on the insert pool if set, a failed vector does not stop the others from being inserted
+func (c *Collection) Insert(items []Item, upsert bool) (err error) {
+
+	for i := range items {
+		if len(items[i].Vector) != c.config.Dim {
+			return fmt.Errorf("id %d has %d dimensions, expected %d", items[i].Id, len(items[i].Vector), c.config.Dim)
+		}
+	}
+
+	if c.config.MaxMemory > 0 && c.memory.Load()+int64(len(items))*c.vectorBytes > c.config.MaxMemory {
+		return fmt.Errorf("%w (%d bytes)", ErrMemoryLimit, c.config.MaxMemory)
+	}
+
+	c.writeGate.RLock()
+	defer c.writeGate.RUnlock()
+
+	// Reserve the ids, so a concurrent insert of the same id fails
+	c.labelsMutex.Lock()
+
+	previous := make([]uint32, len(items))
+	reserved := make(map[uint64]bool, len(items))
+
+	for i, item := range items {
+
+		node, exists := c.nodes[item.Id]
+
+		if reserved[item.Id] || (exists && (!upsert || node == 0)) {
+
+			for i2 := range items[:i] {
+				c.release(items[i2].Id, previous[i2])
+			}
+
+			c.labelsMutex.Unlock()
+
+			return fmt.Errorf("id %d: %w", item.Id, ErrExists)
+
+		}
+
+		reserved[item.Id] = true
+		previous[i] = node
+		c.nodes[item.Id] = 0
+
+	}
+
+	c.labelsMutex.Unlock()
+
+	errs := make([]error, len(items))
+
+	c.insertPool.Run(len(items), func(i int) {
+		errs[i] = c.insert(items[i], previous[i])
+	})
+
+	return errors.Join(errs...)
+
+}
+
+// Delete vectors by id, returns the number deleted (unknown ids are skipped)
+func (c *Collection) Delete(ids []uint64) (deleted int, err error) {
+
+	c.writeGate.RLock()
+	defer c.writeGate.RUnlock()
+
+	for _, id := range ids {
+
+		c.labelsMutex.Lock()
+
+		node, exists := c.nodes[id]
+
+		// Skip ids still being inserted
+		if !exists || node == 0 {
+			c.labelsMutex.Unlock()
+			continue
+		}
+
+		delete(c.nodes, id)
+		delete(c.labels, node)
+
+		c.labelsMutex.Unlock()
+
+		err = c.h.Delete(node)
+
+		if err != nil {
+			return
+		}
+
+		c.deletes.Add(1)
+		deleted++
+
+	}
+
+	return
+
+}
+
+// k nearest vectors to `q`, nearest first
+func (c *Collection) Search(q []float32, k int, efSearch int) (results []Result, err error) {
+
+	results, _, err = c.search(q, k, efSearch, false)
+
+	return
+
+}
+
+// Search, with a trace of the graph traversal (see hnsw.SearchTrace). The trace holds graph node ids, not vector ids
+func (c *Collection) SearchTrace(q []float32, k int, efSearch int) (results []Result, trace *hnsw.Trace, err error) {
+
+	return c.search(q, k, efSearch, true)
+
+}
+
+// Search of each query, on the search pool if set. Results are in query order
+func (c *Collection) SearchBatch(queries [][]float32, k int, efSearch int) (results [][]Result, err error) {
+
+	results = make([][]Result, len(queries))
+	errs := make([]error, len(queries))
+
+	c.searchPool.Run(len(queries), func(i int) {
+		results[i], errs[i] = c.Search(queries[i], k, efSearch)
+	})
+
+	err = errors.Join(errs...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+
+}
+
+// Number of vectors with an id
+func (c *Collection) Len() int {
+
+	c.labelsMutex.RLock()
+	defer c.labelsMutex.RUnlock()
+
+	return len(c.labels)
+
+}
+
+// Bytes held by the index, measured on open and each snapshot and estimated for the inserts since
+func (c *Collection) Memory() int64 {
+	return c.memory.Load()
+}
+
+// Returns true if inserts or deletes were made since the last snapshot
+func (c *Collection) Changed() bool {
+
+	c.snapshotMutex.Lock()
+	defer c.snapshotMutex.Unlock()
+
+	return c.h.Seq() != c.snapshotSeq || c.deletes.Load() != c.snapshotDeletes
+
+}
+
+// Save the index and labels to the collection directory, inserts and deletes are paused only while the graph is copied
+func (c *Collection) Snapshot() (err error) {
+
+	c.snapshotMutex.Lock()
+	defer c.snapshotMutex.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	c.writeGate.Lock()
+
+	s := c.h.Snapshot()
+
+	c.labelsMutex.RLock()
+
+	nodes := make(map[uint64]uint32, len(c.nodes))
+
+	for label, node := range c.nodes {
+		nodes[label] = node
+	}
+
+	c.labelsMutex.RUnlock()
+
+	deletes := c.deletes.Load()
+
+	c.writeGate.Unlock()
+
+	c.memory.Store(c.h.Memory())
+
+	// Written to a new directory, swapped in by renaming a link to it over currentLink. A failed snapshot leaves the
+	// previous one intact
+	snapshot, err := os.MkdirTemp(c.dir, snapshotPrefix)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.Save(filepath.Join(snapshot, indexFile))
+
+	if err == nil {
+		err = writeLabels(filepath.Join(snapshot, labelsFile), nodes)
+	}
+
+	if err == nil {
+		err = swapLink(c.dir, filepath.Base(snapshot))
+	}
+
+	if err != nil {
+		os.RemoveAll(snapshot)
+		return err
+	}
+
+	removeSnapshots(c.dir, filepath.Base(snapshot))
+
+	c.snapshotSeq = s.Seq
+	c.snapshotDeletes = deletes
+	c.snapshotTime = time.Now()
+
+	return nil
+
+}
+
+// Private functions
+
+// Insert a reserved id, releasing it if the insert fails
+func (c *Collection) insert(item Item, previous uint32) error {
+
+	node, err := c.h.Insert(item.Vector)
+
+	c.labelsMutex.Lock()
+
+	if err != nil {
+		c.release(item.Id, previous)
+		c.labelsMutex.Unlock()
+		return err
+	}
+
+	c.nodes[item.Id] = node
+	c.labels[node] = item.Id
+
+	if previous != 0 {
+		delete(c.labels, previous)
+	}
+
+	c.labelsMutex.Unlock()
+
+	c.memory.Add(c.vectorBytes)
+
+	if previous != 0 {
+
+		err = c.h.Delete(previous)
+
+		if err != nil {
+			return err
+		}
+
+		c.deletes.Add(1)
+
+	}
+
+	return nil
+
+}
+
+// Release an id reserved by Insert, restoring its `previous` node if any. The caller must hold labelsMutex
+func (c *Collection) release(id uint64, previous uint32) {
+
+	if previous != 0 {
+		c.nodes[id] = previous
+		return
+	}
+
+	delete(c.nodes, id)
+
+}
+
+// Stop further snapshots, waiting for one in progress
+func (c *Collection) close() {
+
+	c.snapshotMutex.Lock()
+	defer c.snapshotMutex.Unlock()
+
+	c.closed = true
+
+}
+
+// Point currentLink in `dir` at `snapshot` with a single rename
+func swapLink(dir string, snapshot string) error {
+
+	tmp := filepath.Join(dir, currentLink+".tmp")
+
+	os.Remove(tmp)
+
+	err := os.Symlink(snapshot, tmp)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(dir, currentLink))
+
+}
+
+// Remove the snapshot directories other than `current`, and the files of collections saved before snapshot directories
+func removeSnapshots(dir string, current string) {
+
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+
+		name := entry.Name()
+
+		if name == indexFile || name == indexFile+".meta" || name == labelsFile || (entry.IsDir() && name != current && strings.HasPrefix(name, snapshotPrefix)) {
+			os.RemoveAll(filepath.Join(dir, name))
+		}
+
+	}
+
+}
+
+func writeLabels(filename string, nodes map[uint64]uint32) error {
+
+	file, err := os.Create(filename)
+
+	if err != nil {
+		return err
+	}
+
+	err = gob.NewEncoder(file).Encode(nodes)
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+
+}
+
+func parseVectorType(name string) (hnsw.VectorType, error) {
+
+	switch name {
+	case "", "float32":
+		return hnsw.VectorFloat32, nil
+	case "float16":
+		return hnsw.VectorFloat16, nil
+	case "bfloat16":
+		return hnsw.VectorBFloat16, nil
+	}
+
+	return 0, fmt.Errorf("unknown vector type (%s)", name)
+
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Search, traced if `explain` is set
+func (c *Collection) search(q []float32, k int, efSearch int, explain bool) (results []Result, trace *hnsw.Trace, err error) {
+
+	if len(q) != c.config.Dim {
+		return nil, nil, fmt.Errorf("query has %d dimensions, expected %d", len(q), c.config.Dim)
+	}
+
+	// One extra, the entry point placeholder and nodes being inserted have no id
+	topK := queue.NewTopK(k + 1)
+
+	if explain {
+		trace, err = c.h.SearchTrace(&q, topK, max(efSearch, k+1))
+	} else {
+		err = c.h.Search(&q, topK, max(efSearch, k+1))
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results = make([]Result, 0, k)
+
+	c.labelsMutex.RLock()
+	defer c.labelsMutex.RUnlock()
+
+	for _, item := range topK.Sorted() {
+
+		label, ok := c.labels[item.Node]
+
+		if !ok || len(results) == k {
+			continue
+		}
+
+		results = append(results, Result{Id: label, Distance: item.Distance})
+
+	}
+
+	return results, trace, nil
+
+}
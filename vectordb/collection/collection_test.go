@@ -0,0 +1,214 @@
+package collection_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/collection"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Items with ids 1000 + i for each vector
+func newItems(vecs [][]float32) []collection.Item {
+
+	items := make([]collection.Item, len(vecs))
+
+	for i := range vecs {
+		items[i] = collection.Item{Id: uint64(1000 + i), Vector: vecs[i]}
+	}
+
+	return items
+
+}
+
+func Test_Collection(t *testing.T) {
+
+	dir := t.TempDir()
+
+	_, err := collection.Create(dir, collection.Config{})
+	assert.NotNil(t, err)
+
+	_, err = collection.Open(t.TempDir())
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	c, err := collection.Create(dir, collection.Config{Dim: 16, M: 8, EfConstruction: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, 16, c.Config().Mmax0)
+	assert.False(t, c.Changed())
+
+	vecs, _ := vectors.GenerateRandomVectors(500, 16)
+
+	assert.Nil(t, c.Insert(newItems(vecs), false))
+	assert.Equal(t, 500, c.Len())
+	assert.True(t, c.Changed())
+
+	err = c.Insert(newItems(vecs[:1]), false)
+	assert.True(t, errors.Is(err, collection.ErrExists))
+
+	results, err := c.Search(vecs[10], 5, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(results))
+	assert.Equal(t, uint64(1010), results[0].Id)
+
+	traced, trace, err := c.SearchTrace(vecs[10], 5, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, results, traced)
+	assert.Greater(t, trace.Visited, 50)
+
+	// Upsert moves id 1010 to the vector of 1020
+	assert.Nil(t, c.Insert([]collection.Item{{Id: 1010, Vector: vecs[20]}}, true))
+
+	results, err = c.Search(vecs[20], 2, 50)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []uint64{1010, 1020}, []uint64{results[0].Id, results[1].Id})
+
+	deleted, err := c.Delete([]uint64{1020, 1, 1030})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, deleted)
+
+	stats := c.Stats()
+	assert.Equal(t, 502, stats.Nodes)
+	assert.Equal(t, 498, stats.Vectors)
+	assert.Equal(t, 3, stats.Deleted)
+	assert.Equal(t, stats.MaxLevel+1, len(stats.Levels))
+	assert.Greater(t, stats.Memory, int64(500*16*4))
+
+	batch, err := c.SearchBatch([][]float32{vecs[30], vecs[40]}, 3, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(batch))
+	assert.NotEqual(t, uint64(1030), batch[0][0].Id)
+	assert.Equal(t, uint64(1040), batch[1][0].Id)
+
+	_, err = c.SearchBatch([][]float32{vecs[30], {1}}, 3, 50)
+	assert.NotNil(t, err)
+
+	// Reopened from the snapshot
+	assert.Nil(t, c.Snapshot())
+	assert.False(t, c.Changed())
+
+	c2, err := collection.Open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 498, c2.Len())
+	assert.Equal(t, c.Config(), c2.Config())
+
+	results2, err := c2.Search(vecs[40], 5, 50)
+	assert.Nil(t, err)
+
+	results, err = c.Search(vecs[40], 5, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, results, results2)
+
+}
+
+func Test_CollectionSnapshot(t *testing.T) {
+
+	dir := t.TempDir()
+
+	c, err := collection.Create(dir, collection.Config{Dim: 8, M: 8})
+	assert.Nil(t, err)
+
+	vecs, _ := vectors.GenerateRandomVectors(100, 8)
+
+	assert.Nil(t, c.Insert(newItems(vecs[:50]), false))
+	assert.Nil(t, c.Snapshot())
+
+	// A snapshot interrupted before the swap leaves its directory, never read
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "snapshot-interrupted"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "snapshot-interrupted", "index.hnsw"), []byte("partial"), 0644))
+
+	c2, err := collection.Open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, c2.Len())
+
+	assert.Nil(t, c.Insert(newItems(vecs)[50:], false))
+	assert.Nil(t, c.Snapshot())
+
+	// The previous and interrupted snapshots are removed once the new one is current
+	assertSnapshotFiles(t, dir)
+
+	// Collections saved before snapshot directories held the files in their directory
+	current, err := filepath.EvalSymlinks(filepath.Join(dir, "current"))
+	assert.Nil(t, err)
+
+	for _, name := range []string{"index.hnsw", "index.hnsw.meta", "labels.gob"} {
+		assert.Nil(t, os.Rename(filepath.Join(current, name), filepath.Join(dir, name)))
+	}
+
+	assert.Nil(t, os.Remove(filepath.Join(dir, "current")))
+	assert.Nil(t, os.Remove(current))
+
+	c3, err := collection.Open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, c3.Len())
+
+	assert.Nil(t, c3.Insert(newItems(vecs[:1]), true))
+	assert.Nil(t, c3.Snapshot())
+
+	assertSnapshotFiles(t, dir)
+
+	c4, err := collection.Open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, c4.Len())
+
+}
+
+// `dir` holds the config and the current snapshot only
+func assertSnapshotFiles(t *testing.T, dir string) {
+
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+
+	names := []string{}
+
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	assert.Equal(t, 3, len(names), names)
+	assert.Contains(t, names, "config.json")
+	assert.Contains(t, names, "current")
+
+	for _, name := range names {
+		if name != "config.json" && name != "current" {
+			assert.True(t, strings.HasPrefix(name, "snapshot-"), name)
+		}
+	}
+
+}
+
+func Test_CollectionMaxMemory(t *testing.T) {
+
+	vecs, _ := vectors.GenerateRandomVectors(200, 16)
+
+	c, err := collection.Create(t.TempDir(), collection.Config{Dim: 16, MaxMemory: 1 << 15})
+	assert.Nil(t, err)
+
+	items := newItems(vecs)
+
+	// Inserted until the estimate reaches the limit
+	inserted := 0
+
+	for inserted = 0; inserted < len(items); inserted++ {
+
+		err = c.Insert(items[inserted:inserted+1], false)
+
+		if err != nil {
+			break
+		}
+
+	}
+
+	assert.True(t, errors.Is(err, collection.ErrMemoryLimit))
+	assert.Greater(t, inserted, 0)
+	assert.Less(t, inserted, len(items))
+	assert.Equal(t, inserted, c.Len())
+
+	// Measured by the snapshot, close to the estimate
+	assert.Nil(t, c.Snapshot())
+	assert.InDelta(t, 1<<15, c.Memory(), 1<<14)
+
+}
@@ -0,0 +1,308 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("collection not found")
+	ErrCollectionExists = errors.New("collection already exists")
+	ErrManagerClosed    = errors.New("manager is closed")
+)
+
+// Collection names are used as directory names
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Hosts named collections, each in a subdirectory of the manager's directory named after it. Collections are opened on
+// first use and snapshot on their own schedule, batch inserts and searches of all collections share two worker pools
+type Manager struct {
+	dir string
+
+	insertPool *Pool
+	searchPool *Pool
+
+	mutex       sync.Mutex
+	collections map[string]*managed // Open collections
+	closed      bool
+}
+
+// An open collection and its snapshot schedule
+type managed struct {
+	c    *Collection
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Manage the collections in `dir`, created if missing. Batch inserts use `insertWorkers` and searches `searchWorkers`
+// goroutines shared by all collections (runtime.NumCPU if 0)
+func NewManager(dir string, insertWorkers int, searchWorkers int) (m *Manager, err error) {
+
+	err = os.MkdirAll(dir, 0755)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m = &Manager{
+		dir:         dir,
+		insertPool:  NewPool(insertWorkers),
+		searchPool:  NewPool(searchWorkers),
+		collections: make(map[string]*managed),
+	}
+
+	return m, nil
+
+}
+
+// Create collection `name`
+func (m *Manager) Create(name string, config Config) (c *Collection, err error) {
+
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid collection name (%s), use up to 64 letters, digits, _ or -", name)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+
+	dir := filepath.Join(m.dir, name)
+
+	_, err = os.Stat(dir)
+
+	if err == nil {
+		return nil, fmt.Errorf("%s: %w", name, ErrCollectionExists)
+	}
+
+	c, err = Create(dir, config)
+
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	m.add(name, c)
+
+	return c, nil
+
+}
+
+// Collection `name`, opened from its directory if not already open
+func (m *Manager) Open(name string) (c *Collection, err error) {
+
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+
+	if entry, ok := m.collections[name]; ok {
+		return entry.c, nil
+	}
+
+	c, err = Open(filepath.Join(m.dir, name))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	m.add(name, c)
+
+	return c, nil
+
+}
+
+// Names of all collections, open or not, sorted
+func (m *Manager) List() (names []string, err error) {
+
+	entries, err := os.ReadDir(m.dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names = []string{}
+
+	for _, entry := range entries {
+
+		if !entry.IsDir() || !validName.MatchString(entry.Name()) {
+			continue
+		}
+
+		_, err = os.Stat(filepath.Join(m.dir, entry.Name(), configFile))
+
+		if err == nil {
+			names = append(names, entry.Name())
+		}
+
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+
+}
+
+// Delete collection `name` and its directory, the collection must no longer be used
+func (m *Manager) Drop(name string) error {
+
+	if !validName.MatchString(name) {
+		return fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return ErrManagerClosed
+	}
+
+	dir := filepath.Join(m.dir, name)
+
+	if entry, ok := m.collections[name]; ok {
+
+		entry.stopSnapshots()
+		entry.c.close()
+
+		delete(m.collections, name)
+
+	} else if _, err := os.Stat(filepath.Join(dir, configFile)); err != nil {
+		return fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+
+	return os.RemoveAll(dir)
+
+}
+
+// Bytes held by the open collections, see Collection.Memory
+func (m *Manager) Memory() (bytes int64) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, entry := range m.collections {
+		bytes += entry.c.Memory()
+	}
+
+	return
+
+}
+
+// Snapshot the open collections that changed and stop the worker pools. Must be called once inserts and searches have
+// completed, returns the snapshot errors
+func (m *Manager) Close() error {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil
+	}
+
+	m.closed = true
+
+	errs := []error{}
+
+	for name, entry := range m.collections {
+
+		entry.stopSnapshots()
+
+		if entry.c.Changed() {
+
+			err := entry.c.Snapshot()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+
+		}
+
+		entry.c.close()
+
+	}
+
+	m.insertPool.Close()
+	m.searchPool.Close()
+
+	return errors.Join(errs...)
+
+}
+
+// Private functions
+
+// Add an open collection, starting its snapshot schedule. The caller must hold the mutex
+func (m *Manager) add(name string, c *Collection) {
+
+	c.insertPool = m.insertPool
+	c.searchPool = m.searchPool
+
+	entry := &managed{c: c, stop: make(chan struct{}), done: make(chan struct{})}
+
+	m.collections[name] = entry
+
+	go entry.runSnapshots(name)
+
+}
+
+// Snapshot the collection every SnapshotInterval if changed, until stopped
+func (entry *managed) runSnapshots(name string) {
+
+	defer close(entry.done)
+
+	interval := entry.c.config.SnapshotInterval
+
+	if interval <= 0 {
+		<-entry.stop
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-entry.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if !entry.c.Changed() {
+			continue
+		}
+
+		err := entry.c.Snapshot()
+
+		if err != nil {
+			log.Printf("Snapshot of collection %s failed: %v", name, err)
+		}
+
+	}
+
+}
+
+func (entry *managed) stopSnapshots() {
+
+	close(entry.stop)
+	<-entry.done
+
+}
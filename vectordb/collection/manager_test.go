@@ -0,0 +1,110 @@
+package collection_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/collection"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Manager(t *testing.T) {
+
+	dir := t.TempDir()
+
+	m, err := collection.NewManager(dir, 4, 4)
+	assert.Nil(t, err)
+
+	products, err := m.Create("products", collection.Config{Dim: 16, M: 8})
+	assert.Nil(t, err)
+
+	users, err := m.Create("users", collection.Config{Dim: 8, M: 8, SnapshotInterval: 10 * time.Millisecond})
+	assert.Nil(t, err)
+
+	_, err = m.Create("products", collection.Config{Dim: 16})
+	assert.True(t, errors.Is(err, collection.ErrCollectionExists))
+
+	_, err = m.Create("../products", collection.Config{Dim: 16})
+	assert.NotNil(t, err)
+
+	_, err = m.Open("queries")
+	assert.True(t, errors.Is(err, collection.ErrNotFound))
+
+	c, err := m.Open("products")
+	assert.Nil(t, err)
+	assert.Equal(t, products, c)
+
+	names, err := m.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"products", "users"}, names)
+
+	// Concurrent batches of both collections on the shared pools
+	productVecs, _ := vectors.GenerateRandomVectors(500, 16)
+	userVecs, _ := vectors.GenerateRandomVectors(500, 8)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 500; i += 100 {
+
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, products.Insert(newItems(productVecs)[i:i+100], false))
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, users.Insert(newItems(userVecs)[i:i+100], false))
+		}(i)
+
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 500, products.Len())
+	assert.Equal(t, 500, users.Len())
+	assert.Equal(t, products.Memory()+users.Memory(), m.Memory())
+
+	results, err := products.SearchBatch(productVecs[:50], 1, 50)
+	assert.Nil(t, err)
+
+	for i := range results {
+		assert.Equal(t, uint64(1000+i), results[i][0].Id)
+	}
+
+	// Users is snapshot on its schedule, products only on Close
+	assert.Eventually(t, func() bool { return !users.Changed() }, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, products.Changed())
+
+	assert.Nil(t, m.Drop("users"))
+	assert.True(t, errors.Is(m.Drop("users"), collection.ErrNotFound))
+
+	names, err = m.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"products"}, names)
+
+	assert.Nil(t, m.Close())
+	assert.False(t, products.Changed())
+
+	_, err = m.Open("products")
+	assert.True(t, errors.Is(err, collection.ErrManagerClosed))
+
+	// Reopened from the directory
+	m2, err := collection.NewManager(dir, 0, 0)
+	assert.Nil(t, err)
+
+	defer m2.Close()
+
+	products2, err := m2.Open("products")
+	assert.Nil(t, err)
+	assert.Equal(t, 500, products2.Len())
+
+	found, err := products2.Search(productVecs[10], 1, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1010), found[0].Id)
+
+}
@@ -0,0 +1,81 @@
+package collection
+
+import (
+	"runtime"
+	"sync"
+)
+
+// A fixed number of workers shared by collections, bounding the goroutines used by batch inserts and searches across
+// all of them
+type Pool struct {
+	jobs chan func()
+	wg   sync.WaitGroup
+}
+
+// Start a pool of `workers` goroutines, runtime.NumCPU if 0
+func NewPool(workers int) *Pool {
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	p := &Pool{jobs: make(chan func())}
+
+	for i := 0; i < workers; i++ {
+
+		p.wg.Add(1)
+
+		go func() {
+
+			defer p.wg.Done()
+
+			for job := range p.jobs {
+				job()
+			}
+
+		}()
+
+	}
+
+	return p
+
+}
+
+// Run fn(0) .. fn(n-1) on the workers, returns once all have completed. A nil pool runs them on the calling goroutine
+func (p *Pool) Run(n int, fn func(i int)) {
+
+	if p == nil {
+
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+
+		return
+
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+
+		i := i
+
+		p.jobs <- func() {
+			defer wg.Done()
+			fn(i)
+		}
+
+	}
+
+	wg.Wait()
+
+}
+
+// Stop the workers once queued jobs complete, Run must not be called after
+func (p *Pool) Close() {
+
+	close(p.jobs)
+	p.wg.Wait()
+
+}
@@ -0,0 +1,96 @@
+package collection
+
+import (
+	"time"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+)
+
+// Nodes whose top layer is Level, with the connections of all nodes on the level
+type LevelStats struct {
+	Level          int `json:"level"`
+	Nodes          int `json:"nodes"`
+	Connections    int `json:"connections"`
+	AvgConnections int `json:"avg_connections"`
+}
+
+type Stats struct {
+	Dim        int    `json:"dim"`
+	VectorType string `json:"vector_type"`
+
+	M              int     `json:"m"`
+	Mmax           int     `json:"mmax"`
+	Mmax0          int     `json:"mmax0"`
+	EfConstruction int     `json:"ef_construction"`
+	Ep             int64   `json:"ep"`
+	MaxLevel       int     `json:"max_level"`
+	Heuristic      bool    `json:"heuristic"`
+	Ml             float64 `json:"ml"`
+
+	Nodes   int          `json:"nodes"`   // Including deleted nodes and the entry point placeholder
+	Vectors int          `json:"vectors"` // Vectors with an id, returned by search
+	Deleted int          `json:"deleted"`
+	Levels  []LevelStats `json:"levels"`
+	Memory  int64        `json:"memory"` // Bytes, see Collection.Memory
+
+	Graph hnsw.GraphStats `json:"graph"` // Node counts and degree distribution of every level
+
+	Seq          uint64    `json:"seq"`
+	SnapshotSeq  uint64    `json:"snapshot_seq"`
+	SnapshotTime time.Time `json:"snapshot_time"`
+}
+
+// Index parameters and the connections of each level
+func (c *Collection) Stats() (stats Stats) {
+
+	h := c.h
+
+	// Read under the index's own lock, stats do not wait for in-flight inserts
+	stats.Seq = h.Seq()
+
+	graph := h.Stats()
+
+	stats.Dim = c.config.Dim
+	stats.VectorType = graph.VectorType
+
+	stats.M = graph.M
+	stats.Mmax = graph.Mmax
+	stats.Mmax0 = graph.Mmax0
+	stats.EfConstruction = graph.Efconstruction
+	stats.Ep = graph.Ep
+	stats.MaxLevel = graph.MaxLevel
+	stats.Heuristic = graph.Heuristic
+	stats.Ml = graph.Ml
+
+	stats.Levels = make([]LevelStats, len(graph.Levels))
+
+	for i, level := range graph.Levels {
+
+		// Nodes on the level less those on the level above
+		stats.Levels[i] = LevelStats{
+			Level:          i,
+			Nodes:          level.Nodes,
+			Connections:    level.Edges,
+			AvgConnections: int(level.Degree.Avg),
+		}
+
+		if i+1 < len(graph.Levels) {
+			stats.Levels[i].Nodes -= graph.Levels[i+1].Nodes
+		}
+
+	}
+
+	stats.Nodes = graph.Nodes
+	stats.Vectors = c.Len()
+	stats.Deleted = graph.Deleted
+	stats.Memory = c.Memory()
+	stats.Graph = graph
+
+	c.snapshotMutex.Lock()
+	stats.SnapshotSeq = c.snapshotSeq
+	stats.SnapshotTime = c.snapshotTime
+	c.snapshotMutex.Unlock()
+
+	return
+
+}
@@ -0,0 +1,33 @@
+package distance
+
+import (
+	"errors"
+	"math"
+)
+
+// Cosine distance (1 - cosine similarity), 0 for vectors in the same direction and 2 for opposite. A zero vector has a
+// distance of 1 to every vector
+func Cosine(queryPoint *[]float32, vectorToCompare *[]float32) (distance float32, err error) {
+
+	if len(*queryPoint) != len(*vectorToCompare) {
+		return 0, errors.New("Must compare two vectors of the same dimension")
+	}
+
+	var dot, normQ, normV float64
+
+	for i := range *queryPoint {
+		q := float64((*queryPoint)[i])
+		v := float64((*vectorToCompare)[i])
+
+		dot += q * v
+		normQ += q * q
+		normV += v * v
+	}
+
+	if normQ == 0 || normV == 0 {
+		return 1, nil
+	}
+
+	return float32(1 - dot/math.Sqrt(normQ*normV)), nil
+
+}
@@ -0,0 +1,32 @@
+package distance_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Cosine(t *testing.T) {
+
+	x := []float32{1, 0}
+
+	for _, test := range []struct {
+		y        []float32
+		distance float32
+	}{
+		{[]float32{2, 0}, 0},
+		{[]float32{0, 3}, 1},
+		{[]float32{-1, 0}, 2},
+		{[]float32{1, 1}, 1 - 1/1.4142135},
+		{[]float32{0, 0}, 1},
+	} {
+		dist, err := distance.Cosine(&x, &test.y)
+		assert.Nil(t, err)
+		assert.InDelta(t, test.distance, dist, 1e-6)
+	}
+
+	_, err := distance.Cosine(&x, &[]float32{1})
+	assert.NotNil(t, err)
+
+}
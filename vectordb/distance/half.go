@@ -0,0 +1,132 @@
+package distance
+
+import (
+	"errors"
+	"math"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+)
+
+// Squared L2 distance kernels for vectors stored as float16 or bfloat16, each component is converted to float32 on
+// the fly. float16 uses a lookup table (256KB) as the conversion branches on the exponent, bfloat16 is a shift.
+
+var float16Table [1 << 16]float32
+
+var errDimension = errors.New("Must compare two vectors of the same dimension")
+
+func init() {
+	for i := range float16Table {
+		float16Table[i] = vectors.Float16ToFloat32(uint16(i))
+	}
+}
+
+func L2_Float16(queryPoint *[]float32, vectorToCompare *[]uint16) (distance float32, err error) {
+
+	q := *queryPoint
+
+	if len(q) != len(*vectorToCompare) {
+		return 0, errDimension
+	}
+
+	v := (*vectorToCompare)[:len(q)]
+
+	i := 0
+
+	// Unrolled, 4 independent sums
+	var d0, d1, d2, d3 float32
+
+	for ; i+4 <= len(q); i += 4 {
+		a := q[i] - float16Table[v[i]]
+		b := q[i+1] - float16Table[v[i+1]]
+		c := q[i+2] - float16Table[v[i+2]]
+		d := q[i+3] - float16Table[v[i+3]]
+
+		d0 += a * a
+		d1 += b * b
+		d2 += c * c
+		d3 += d * d
+	}
+
+	for ; i < len(q); i++ {
+		a := q[i] - float16Table[v[i]]
+		d0 += a * a
+	}
+
+	return d0 + d1 + d2 + d3, nil
+
+}
+
+func L2_BFloat16(queryPoint *[]float32, vectorToCompare *[]uint16) (distance float32, err error) {
+
+	q := *queryPoint
+
+	if len(q) != len(*vectorToCompare) {
+		return 0, errDimension
+	}
+
+	v := (*vectorToCompare)[:len(q)]
+
+	i := 0
+
+	var d0, d1, d2, d3 float32
+
+	for ; i+4 <= len(q); i += 4 {
+		a := q[i] - math.Float32frombits(uint32(v[i])<<16)
+		b := q[i+1] - math.Float32frombits(uint32(v[i+1])<<16)
+		c := q[i+2] - math.Float32frombits(uint32(v[i+2])<<16)
+		d := q[i+3] - math.Float32frombits(uint32(v[i+3])<<16)
+
+		d0 += a * a
+		d1 += b * b
+		d2 += c * c
+		d3 += d * d
+	}
+
+	for ; i < len(q); i++ {
+		a := q[i] - math.Float32frombits(uint32(v[i])<<16)
+		d0 += a * a
+	}
+
+	return d0 + d1 + d2 + d3, nil
+
+}
+
+// Distance between two float16 vectors
+func L2_Float16x2(x *[]uint16, y *[]uint16) (distance float32, err error) {
+
+	a := *x
+
+	if len(a) != len(*y) {
+		return 0, errDimension
+	}
+
+	b := (*y)[:len(a)]
+
+	for i := range a {
+		d := float16Table[a[i]] - float16Table[b[i]]
+		distance += d * d
+	}
+
+	return distance, nil
+
+}
+
+// Distance between two bfloat16 vectors
+func L2_BFloat16x2(x *[]uint16, y *[]uint16) (distance float32, err error) {
+
+	a := *x
+
+	if len(a) != len(*y) {
+		return 0, errDimension
+	}
+
+	b := (*y)[:len(a)]
+
+	for i := range a {
+		d := math.Float32frombits(uint32(a[i])<<16) - math.Float32frombits(uint32(b[i])<<16)
+		distance += d * d
+	}
+
+	return distance, nil
+
+}
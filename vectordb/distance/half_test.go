@@ -0,0 +1,89 @@
+package distance_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_L2_Half(t *testing.T) {
+
+	v, err := vectors.GenerateRandomVectors(2, 37)
+	assert.Nil(t, err)
+
+	f16 := vectors.ToFloat16(v[1])
+	bf16 := vectors.ToBFloat16(v[1])
+
+	// Exact distance to the converted vector
+	decoded := vectors.FromFloat16(f16)
+	expected, _ := distance.L2_Opt(&v[0], &decoded)
+
+	dist, err := distance.L2_Float16(&v[0], &f16)
+	assert.Nil(t, err)
+	assert.InDelta(t, expected, dist, 1e-5)
+
+	decoded = vectors.FromBFloat16(bf16)
+	expected, _ = distance.L2_Opt(&v[0], &decoded)
+
+	dist, err = distance.L2_BFloat16(&v[0], &bf16)
+	assert.Nil(t, err)
+	assert.InDelta(t, expected, dist, 1e-5)
+
+	// Both vectors converted
+	f16q := vectors.ToFloat16(v[0])
+	bf16q := vectors.ToBFloat16(v[0])
+
+	exact, _ := distance.L2_Opt(&v[0], &v[1])
+
+	dist, err = distance.L2_Float16x2(&f16q, &f16)
+	assert.Nil(t, err)
+	assert.InDelta(t, exact, dist, 1e-2)
+
+	dist, err = distance.L2_BFloat16x2(&bf16q, &bf16)
+	assert.Nil(t, err)
+	assert.InDelta(t, exact, dist, 1e-1)
+
+	// Vectors of different dimensions
+	short := f16[:10]
+
+	_, err = distance.L2_Float16(&v[0], &short)
+	assert.NotNil(t, err)
+
+	_, err = distance.L2_BFloat16(&v[0], &short)
+	assert.NotNil(t, err)
+
+	_, err = distance.L2_Float16x2(&f16q, &short)
+	assert.NotNil(t, err)
+
+	_, err = distance.L2_BFloat16x2(&short, &bf16q)
+	assert.NotNil(t, err)
+
+}
+
+func Benchmark_L2_Float16(b *testing.B) {
+
+	v, _ := vectors.GenerateRandomVectors(2, 128)
+	f16 := vectors.ToFloat16(v[1])
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		distance.L2_Float16(&v[0], &f16)
+	}
+
+}
+
+func Benchmark_L2_BFloat16(b *testing.B) {
+
+	v, _ := vectors.GenerateRandomVectors(2, 128)
+	bf16 := vectors.ToBFloat16(v[1])
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		distance.L2_BFloat16(&v[0], &bf16)
+	}
+
+}
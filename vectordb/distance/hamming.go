@@ -0,0 +1,34 @@
+package distance
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Number of differing bits between two binary vectors of the same length
+func Hamming(a []uint64, b []uint64) (distance int) {
+
+	for i := range a {
+		distance += bits.OnesCount64(a[i] ^ b[i])
+	}
+
+	return distance
+
+}
+
+// Hamming distance between binary vectors packed as bytes, compared 8 bytes at a time
+func HammingBytes(a []uint8, b []uint8) (distance int) {
+
+	i := 0
+
+	for ; i+8 <= len(a); i += 8 {
+		distance += bits.OnesCount64(binary.LittleEndian.Uint64(a[i:]) ^ binary.LittleEndian.Uint64(b[i:]))
+	}
+
+	for ; i < len(a); i++ {
+		distance += bits.OnesCount8(a[i] ^ b[i])
+	}
+
+	return distance
+
+}
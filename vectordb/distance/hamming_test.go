@@ -0,0 +1,28 @@
+package distance_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Hamming(t *testing.T) {
+
+	assert.Equal(t, 0, distance.Hamming([]uint64{0xff, 1}, []uint64{0xff, 1}))
+	assert.Equal(t, 64, distance.Hamming([]uint64{0}, []uint64{^uint64(0)}))
+	assert.Equal(t, 3, distance.Hamming([]uint64{0b1010, 0}, []uint64{0b0110, 1 << 63}))
+
+}
+
+func Test_HammingBytes(t *testing.T) {
+
+	a := []uint8{0xff, 0, 0, 0, 0, 0, 0, 1, 0x0f, 0x01}
+	b := []uint8{0x0f, 0, 0, 0, 0, 0, 0, 0, 0xf0, 0x01}
+
+	// 4 + 1 bits in the first 8 bytes, 8 bits in the tail
+	assert.Equal(t, 13, distance.HammingBytes(a, b))
+
+	assert.Equal(t, distance.Hamming([]uint64{0x01000000000000ff}, []uint64{0x0f}), distance.HammingBytes(a[:8], b[:8]))
+
+}
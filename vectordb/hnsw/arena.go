@@ -0,0 +1,191 @@
+package hnsw
+
+import "unsafe"
+
+// Vectors and level 0 links are held in contiguous arenas instead of a slice per node, as hnswlib does, reducing the
+// number of heap objects and keeping the data read by SearchLayer together. NodeList.Nodes holds the layer, id, codes
+// and upper level connections of each node, use PeekNode for a node including its vector and level 0 links.
+
+// Size the arenas for `dim` dimensional vectors, with up to `mmax0` links per node on level 0
+func (l *NodeList) init(dim int, mmax0 int) {
+
+	l.dim = dim
+	l.stride = mmax0 + 1
+
+	l.Nodes = nil
+	l.vectors = nil
+	l.half = nil
+	l.links0 = nil
+	l.tenants = make(map[uint32]*tenant)
+
+}
+
+// Append a node, moving its vector and level 0 links into the arenas, the caller must hold the mutex
+func (l *NodeList) append(node Node) {
+
+	l.vectors = append(l.vectors, node.Vectors...)
+	l.half = append(l.half, node.Half...)
+
+	// Level 0 links, count followed by up to mmax0 ids
+	l.links0 = append(l.links0, make([]uint32, l.stride)...)
+
+	node.Vectors = nil
+	node.Half = nil
+
+	// Copy the list of levels, so the caller cannot modify the stored node's connections directly
+	connections := node.Connections
+	node.Connections = make([][]uint32, len(connections))
+	copy(node.Connections, connections)
+
+	l.Nodes = append(l.Nodes, node)
+
+	if node.Tenant != 0 {
+		l.addTenantNode(node.Tenant, uint32(len(l.Nodes)-1), node.Deleted)
+	}
+
+	if len(connections) > 0 {
+		id := uint32(len(l.Nodes) - 1)
+		l.Nodes[id].Connections[0] = nil
+		l.setLinks(id, 0, connections[0])
+	}
+
+}
+
+// Replace all nodes, e.g from Load, level 0 is sized for the largest of `mmax0` and the nodes' links
+func (l *NodeList) setNodes(nodes []Node, mmax0 int) {
+
+	dim := 0
+
+	for i := range nodes {
+
+		if len(nodes[i].Connections) > 0 && len(nodes[i].Connections[0]) > mmax0 {
+			mmax0 = len(nodes[i].Connections[0])
+		}
+
+		if len(nodes[i].Vectors) > 0 {
+			dim = len(nodes[i].Vectors)
+		} else if len(nodes[i].Half) > 0 {
+			dim = len(nodes[i].Half)
+		}
+
+	}
+
+	l.init(dim, mmax0)
+
+	l.Nodes = make([]Node, 0, len(nodes))
+
+	for i := range nodes {
+		nodes[i].Id = uint32(i)
+		l.append(nodes[i])
+	}
+
+}
+
+// Float32 vector of node `id`, a view of the arena (nil once vectors are released)
+func (l *NodeList) vector(id uint32) []float32 {
+
+	if len(l.vectors) == 0 {
+		return nil
+	}
+
+	start := int(id) * l.dim
+
+	return l.vectors[start : start+l.dim : start+l.dim]
+
+}
+
+// float16 or bfloat16 vector of node `id`, a view of the arena (nil once vectors are released)
+func (l *NodeList) halfVector(id uint32) []uint16 {
+
+	if len(l.half) == 0 {
+		return nil
+	}
+
+	start := int(id) * l.dim
+
+	return l.half[start : start+l.dim : start+l.dim]
+
+}
+
+// Links of node `id` at `level`, level 0 is a view of the arena which must not be appended to in place
+func (l *NodeList) links(id uint32, level int) []uint32 {
+
+	if level == 0 {
+		row := l.links0[int(id)*l.stride : int(id+1)*l.stride]
+		count := row[0]
+		return row[1 : 1+count : 1+count]
+	}
+
+	if level >= len(l.Nodes[id].Connections) {
+		return nil
+	}
+
+	return l.Nodes[id].Connections[level]
+
+}
+
+// Replace the links of node `id` at `level`, level 0 is limited to mmax0 links
+func (l *NodeList) setLinks(id uint32, level int, links []uint32) {
+
+	if level == 0 {
+		row := l.links0[int(id)*l.stride : int(id+1)*l.stride]
+		count := copy(row[1:], links)
+		row[0] = uint32(count)
+		return
+	}
+
+	l.Nodes[id].Connections[level] = links
+
+}
+
+// Full copy of node `id`, with its vector (shared with the arena) and the links of every level
+func (l *NodeList) node(id uint32) (node Node) {
+
+	node = l.Nodes[id]
+
+	node.Vectors = l.vector(id)
+	node.Half = l.halfVector(id)
+
+	node.Connections = make([][]uint32, len(l.Nodes[id].Connections))
+	copy(node.Connections, l.Nodes[id].Connections)
+
+	if len(node.Connections) == 0 {
+		node.Connections = make([][]uint32, 1)
+	}
+
+	level0 := l.links(id, 0)
+	node.Connections[0] = make([]uint32, len(level0))
+	copy(node.Connections[0], level0)
+
+	return node
+
+}
+
+// Bytes held by the index: the node list, the vector and level 0 link arenas, quantized codes and the upper level
+// connections. Arenas are counted by capacity, so include space reserved by append
+func (h *HNSW) Memory() int64 {
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	l := &h.NodeList
+
+	bytes := int64(cap(l.Nodes))*int64(unsafe.Sizeof(Node{})) + int64(cap(l.vectors))*4 + int64(cap(l.half))*2 + int64(cap(l.links0))*4
+
+	for i := range l.Nodes {
+
+		bytes += int64(cap(l.Nodes[i].Codes)) + int64(cap(l.Nodes[i].Connections))*int64(unsafe.Sizeof([]uint32{}))
+
+		for _, links := range l.Nodes[i].Connections {
+			bytes += int64(cap(links)) * 4
+		}
+
+	}
+
+	for _, t := range l.tenants {
+		bytes += int64(cap(t.nodes)) * 4
+	}
+
+	return bytes
+
+}
@@ -0,0 +1,201 @@
+package hnsw
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Reachability of the nodes of a level, returned by HNSW.Connectivity
+type ConnectivityReport struct {
+	Level            int      `json:"level"`
+	Nodes            int      `json:"nodes"`             // Nodes on the level, including deleted nodes
+	Orphans          []uint32 `json:"orphans"`           // Nodes with no incoming links, other than the entry point
+	Unreachable      []uint32 `json:"unreachable"`       // Nodes not reachable from the entry point, never returned by Search
+	Components       int      `json:"components"`        // Weakly connected components, links taken as undirected
+	LargestComponent int      `json:"largest_component"` // Nodes in the largest component
+}
+
+// Find the orphans and unreachable nodes of `level` (breadth first from the entry point) and its weakly connected
+// components. Safe to call while inserts are running, nodes being inserted may be reported as orphans
+func (h *HNSW) Connectivity(level int) (report ConnectivityReport, err error) {
+
+	h.mutex.RLock()
+	ep, maxLevel := h.Ep, h.Maxlevel
+	h.mutex.RUnlock()
+
+	if level < 0 || level > maxLevel {
+		return report, fmt.Errorf("level %d does not exist, the graph has levels 0 to %d", level, maxLevel)
+	}
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	l := &h.NodeList
+
+	report.Level = level
+	report.Orphans = []uint32{}
+	report.Unreachable = []uint32{}
+
+	incoming := make([]int, len(l.Nodes))
+
+	// Union-find of the components, each node is its own parent until linked
+	parent := make([]uint32, len(l.Nodes))
+
+	for i := range parent {
+		parent[i] = uint32(i)
+	}
+
+	for i := range l.Nodes {
+
+		if l.Nodes[i].Layer < level {
+			continue
+		}
+
+		report.Nodes++
+
+		for _, link := range l.links(uint32(i), level) {
+
+			if int(link) >= len(l.Nodes) {
+				continue
+			}
+
+			incoming[link]++
+			union(parent, uint32(i), link)
+
+		}
+
+	}
+
+	visited := make([]bool, len(l.Nodes))
+	h.reach(uint32(ep), level, visited)
+
+	sizes := map[uint32]int{}
+
+	for i := range l.Nodes {
+
+		if l.Nodes[i].Layer < level {
+			continue
+		}
+
+		if incoming[i] == 0 && int64(i) != ep {
+			report.Orphans = append(report.Orphans, uint32(i))
+		}
+
+		if !visited[i] {
+			report.Unreachable = append(report.Unreachable, uint32(i))
+		}
+
+		root := find(parent, uint32(i))
+		sizes[root]++
+
+		report.LargestComponent = max(report.LargestComponent, sizes[root])
+
+	}
+
+	report.Components = len(sizes)
+
+	return
+
+}
+
+// Passes of Reconnect, a back-link may prune the only link to another node which is linked again by the next pass
+const ReconnectPasses = 8
+
+// Link the orphans of every level back from their own neighbours, then link any node still unreachable from its nearest
+// reachable neighbours (see Repair), repeated until no node is linked or after ReconnectPasses. Writers are paused,
+// returns the number of nodes linked
+func (h *HNSW) Reconnect() (reconnected int, err error) {
+
+	// Back-links are pruned by distance
+	if h.VectorsReleased {
+		return 0, errors.New("cannot reconnect once vectors are released")
+	}
+
+	h.writeGate.Lock()
+	defer h.writeGate.Unlock()
+
+	h.mutex.RLock()
+	ep, maxLevel := h.Ep, h.Maxlevel
+	h.mutex.RUnlock()
+
+	h.NodeList.mutex.Lock()
+	defer h.NodeList.mutex.Unlock()
+
+	for pass := 0; pass < ReconnectPasses; pass++ {
+
+		linked := h.reconnectOrphans(ep, maxLevel) + h.reconnectUnreachable(ep, maxLevel)
+
+		if linked == 0 {
+			break
+		}
+
+		reconnected += linked
+
+	}
+
+	return
+
+}
+
+// Private functions
+
+// Back-link the nodes with no incoming links on each level from their own neighbours, returns the number linked
+func (h *HNSW) reconnectOrphans(ep int64, maxLevel int) (reconnected int) {
+
+	l := &h.NodeList
+
+	for level := maxLevel; level >= 0; level-- {
+
+		incoming := h.incomingLinks(level)
+
+		for i := range l.Nodes {
+
+			if incoming[i] > 0 || int64(i) == ep || l.Nodes[i].Layer < level {
+				continue
+			}
+
+			// Its neighbours are the nodes it was linked to on insert, nearest first. Invalid links are left to Repair
+			links := []uint32{}
+
+			for _, link := range l.links(uint32(i), level) {
+				if int(link) < len(l.Nodes) && l.Nodes[link].Layer >= level && link != uint32(i) {
+					links = append(links, link)
+				}
+			}
+
+			if len(links) == 0 {
+				continue
+			}
+
+			h.backLink(uint32(i), h.nearestLinks(uint32(i), links, len(links)), level, incoming)
+			reconnected++
+
+		}
+
+	}
+
+	return
+
+}
+
+// Root of the component of `id`, halving the path on the way
+func find(parent []uint32, id uint32) uint32 {
+
+	for parent[id] != id {
+		parent[id] = parent[parent[id]]
+		id = parent[id]
+	}
+
+	return id
+
+}
+
+func union(parent []uint32, a uint32, b uint32) {
+
+	rootA, rootB := find(parent, a), find(parent, b)
+
+	if rootA != rootB {
+		parent[rootB] = rootA
+	}
+
+}
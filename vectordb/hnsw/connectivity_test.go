@@ -0,0 +1,88 @@
+package hnsw_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Connectivity(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(3000, 32)
+	assert.Nil(t, err)
+
+	// Mmax0 = M prunes heavily, orphaning nodes on level 0
+	h, err := hnsw.New(8, 8, 8, 40, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	report, err := h.Connectivity(0)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, report.Level)
+	assert.Equal(t, 3001, report.Nodes)
+	assert.Greater(t, len(report.Orphans), 0)
+	assert.GreaterOrEqual(t, report.Components, 1)
+	assert.LessOrEqual(t, report.LargestComponent, report.Nodes)
+
+	// An orphan cannot be reached
+	for _, id := range report.Orphans {
+		assert.Contains(t, report.Unreachable, id)
+	}
+
+	assert.Equal(t, len(report.Unreachable), h.Validate().Unreachable[0])
+
+	upper, err := h.Connectivity(1)
+	assert.Nil(t, err)
+	assert.Less(t, upper.Nodes, report.Nodes)
+
+	// Levels outside the graph
+	_, err = h.Connectivity(-1)
+	assert.NotNil(t, err)
+
+	_, err = h.Connectivity(h.MaxLevel() + 1)
+	assert.NotNil(t, err)
+
+	reconnected, err := h.Reconnect()
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, reconnected, len(report.Orphans))
+
+	after, err := h.Connectivity(0)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, len(after.Orphans))
+	assert.Less(t, len(after.Unreachable), len(report.Unreachable)/10+1)
+	assert.Equal(t, 1, after.Components)
+
+	// Degree bounds still hold
+	assert.Equal(t, 0, h.Validate().Count(hnsw.IssueDegree))
+
+	// Nodes linked again are found, other than the entry point placeholder
+	found, searched := 0, 0
+
+	for _, id := range report.Unreachable {
+
+		if id == 0 {
+			continue
+		}
+
+		searched++
+		results := queue.NewTopK(1)
+		assert.Nil(t, h.Search(&vecs[id-1], results, 100))
+
+		if results.Sorted()[0].Node == id {
+			found++
+		}
+
+	}
+
+	assert.Greater(t, found, searched*3/4)
+
+}
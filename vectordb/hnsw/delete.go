@@ -0,0 +1,49 @@
+package hnsw
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Mark node `id` as deleted, as hnswlib markDelete does. The node is kept in the graph so searches can still traverse
+// through it, but it is no longer returned by Search or BruteSearch. Deletes are saved with the index.
+func (h *HNSW) Delete(id uint32) error {
+
+	// Block while a snapshot is being taken, as Insert does
+	h.writeGate.RLock()
+	defer h.writeGate.RUnlock()
+
+	h.NodeList.mutex.Lock()
+	defer h.NodeList.mutex.Unlock()
+
+	if id == 0 && hasPlaceholder(h.seq, len(h.NodeList.Nodes)) {
+		return errors.New("node 0 is the entry point placeholder and cannot be deleted")
+	}
+
+	if int(id) >= len(h.NodeList.Nodes) {
+		return fmt.Errorf("node %d does not exist", id)
+	}
+
+	if h.NodeList.Nodes[id].Deleted {
+		return fmt.Errorf("node %d is already deleted", id)
+	}
+
+	h.NodeList.Nodes[id].Deleted = true
+
+	if t, ok := h.NodeList.tenants[h.NodeList.Nodes[id].Tenant]; ok {
+		t.live--
+	}
+
+	return nil
+
+}
+
+// Returns true if node `id` is marked as deleted
+func (h *HNSW) Deleted(id uint32) bool {
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	return int(id) < len(h.NodeList.Nodes) && h.NodeList.Nodes[id].Deleted
+
+}
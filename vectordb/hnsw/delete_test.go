@@ -0,0 +1,59 @@
+package hnsw_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Delete(t *testing.T) {
+
+	h, vecs := newSearchIndex(t, 1000, 16)
+
+	// Node ids follow insert order, after the entry point placeholder
+	results := queue.NewTopK(1)
+	err := h.Search(&vecs[9], results, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), results.Sorted()[0].Node)
+
+	assert.Nil(t, h.Delete(10))
+	assert.True(t, h.Deleted(10))
+	assert.False(t, h.Deleted(11))
+
+	assert.NotNil(t, h.Delete(10))
+	assert.NotNil(t, h.Delete(0))
+	assert.NotNil(t, h.Delete(5000))
+
+	// No longer returned, the graph is still traversed through it
+	results = queue.NewTopK(10)
+	err = h.Search(&vecs[9], results, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, results.Len())
+
+	for _, item := range results.Sorted() {
+		assert.NotEqual(t, uint32(10), item.Node)
+	}
+
+	brute, err := h.BruteSearch(&vecs[9], 10)
+	assert.Nil(t, err)
+
+	for _, item := range brute.Sorted() {
+		assert.NotEqual(t, uint32(10), item.Node)
+	}
+
+	// Deletes are saved with the index
+	filename := filepath.Join(t.TempDir(), "deleted.hnsw")
+
+	err = h.Save(filename)
+	assert.Nil(t, err)
+
+	h2, err := hnsw.Load(filename)
+	assert.Nil(t, err)
+
+	assert.True(t, h2.Deleted(10))
+	assert.False(t, h2.Deleted(11))
+
+}
@@ -0,0 +1,402 @@
+package hnsw
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Level of ExportOptions to export every level
+const AllLevels = -1
+
+// Part of the graph written by ExportDOT, ExportGraphML and ExportEdgeList
+type ExportOptions struct {
+	Level      int  // Level to export, AllLevels for every level
+	MaxNodes   int  // Nodes reached first breadth first from the entry point are kept, 0 for all nodes
+	Projection bool // Add x and y attributes, the projection of each node's vector on its two principal components
+}
+
+// Write the graph in the Graphviz DOT format, a digraph with the level, degree and deleted attributes on each node (and
+// pos for neato if projected) and the level on each edge. Returns the number of nodes and edges written
+func (h *HNSW) ExportDOT(w io.Writer, options ExportOptions) (nodes int, edges int, err error) {
+
+	g, err := h.exportGraph(options)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "digraph hnsw {")
+
+	for _, node := range g.nodes {
+
+		fmt.Fprintf(b, "  %d [level=%d, degree=%d, deleted=%v", node.id, node.level, node.degree, node.deleted)
+
+		if options.Projection {
+			fmt.Fprintf(b, ", pos=\"%s,%s!\"", formatCoord(node.x), formatCoord(node.y))
+		}
+
+		fmt.Fprintln(b, "];")
+
+	}
+
+	for _, edge := range g.edges {
+		fmt.Fprintf(b, "  %d -> %d [level=%d];\n", edge.source, edge.target, edge.level)
+	}
+
+	fmt.Fprintln(b, "}")
+
+	return len(g.nodes), len(g.edges), b.Flush()
+
+}
+
+// Write the graph as GraphML, with the same attributes as ExportDOT (x and y if projected). Returns the number of nodes
+// and edges written
+func (h *HNSW) ExportGraphML(w io.Writer, options ExportOptions) (nodes int, edges int, err error) {
+
+	g, err := h.exportGraph(options)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(b, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(b, `  <key id="level" for="node" attr.name="level" attr.type="int"/>`)
+	fmt.Fprintln(b, `  <key id="degree" for="node" attr.name="degree" attr.type="int"/>`)
+	fmt.Fprintln(b, `  <key id="deleted" for="node" attr.name="deleted" attr.type="boolean"/>`)
+
+	if options.Projection {
+		fmt.Fprintln(b, `  <key id="x" for="node" attr.name="x" attr.type="double"/>`)
+		fmt.Fprintln(b, `  <key id="y" for="node" attr.name="y" attr.type="double"/>`)
+	}
+
+	fmt.Fprintln(b, `  <key id="edge_level" for="edge" attr.name="level" attr.type="int"/>`)
+	fmt.Fprintln(b, `  <graph id="hnsw" edgedefault="directed">`)
+
+	for _, node := range g.nodes {
+
+		fmt.Fprintf(b, `    <node id="n%d"><data key="level">%d</data><data key="degree">%d</data><data key="deleted">%v</data>`, node.id, node.level, node.degree, node.deleted)
+
+		if options.Projection {
+			fmt.Fprintf(b, `<data key="x">%s</data><data key="y">%s</data>`, formatCoord(node.x), formatCoord(node.y))
+		}
+
+		fmt.Fprintln(b, `</node>`)
+
+	}
+
+	for _, edge := range g.edges {
+		fmt.Fprintf(b, "    <edge source=\"n%d\" target=\"n%d\"><data key=\"edge_level\">%d</data></edge>\n", edge.source, edge.target, edge.level)
+	}
+
+	fmt.Fprintln(b, `  </graph>`)
+	fmt.Fprintln(b, `</graphml>`)
+
+	return len(g.nodes), len(g.edges), b.Flush()
+
+}
+
+// Write the edges as CSV with a header, one row per link with the level and degree of both nodes (and their x and y if
+// projected). Nodes without links are not written, returns the number of nodes with a link and of edges written
+func (h *HNSW) ExportEdgeList(w io.Writer, options ExportOptions) (nodes int, edges int, err error) {
+
+	g, err := h.exportGraph(options)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	c := csv.NewWriter(w)
+
+	header := []string{"source", "target", "level", "source_level", "source_degree", "target_level", "target_degree"}
+
+	if options.Projection {
+		header = append(header, "source_x", "source_y", "target_x", "target_y")
+	}
+
+	c.Write(header)
+
+	// Edges are between exported nodes only
+	index := make(map[uint32]int, len(g.nodes))
+
+	for i, node := range g.nodes {
+		index[node.id] = i
+	}
+
+	linked := map[uint32]bool{}
+
+	for _, edge := range g.edges {
+
+		source, target := g.nodes[index[edge.source]], g.nodes[index[edge.target]]
+		linked[edge.source], linked[edge.target] = true, true
+
+		row := []string{
+			strconv.Itoa(int(edge.source)),
+			strconv.Itoa(int(edge.target)),
+			strconv.Itoa(edge.level),
+			strconv.Itoa(source.level),
+			strconv.Itoa(source.degree),
+			strconv.Itoa(target.level),
+			strconv.Itoa(target.degree),
+		}
+
+		if options.Projection {
+			row = append(row, formatCoord(source.x), formatCoord(source.y), formatCoord(target.x), formatCoord(target.y))
+		}
+
+		c.Write(row)
+
+	}
+
+	c.Flush()
+
+	return len(linked), len(g.edges), c.Error()
+
+}
+
+// Private functions
+
+type exportNode struct {
+	id      uint32
+	level   int // Top layer of the node
+	degree  int // Links on the exported level, level 0 if all levels are exported
+	deleted bool
+	x, y    float64
+}
+
+type exportEdge struct {
+	source uint32
+	target uint32
+	level  int
+}
+
+type exportedGraph struct {
+	nodes []exportNode // Ordered by id
+	edges []exportEdge
+}
+
+// Nodes and edges selected by `options`
+func (h *HNSW) exportGraph(options ExportOptions) (g exportedGraph, err error) {
+
+	h.mutex.RLock()
+	ep, maxLevel := h.Ep, h.Maxlevel
+	h.mutex.RUnlock()
+
+	if options.Level < AllLevels || options.Level > maxLevel {
+		return g, fmt.Errorf("level %d does not exist, the graph has levels 0 to %d", options.Level, maxLevel)
+	}
+
+	if options.Projection && h.VectorsReleased {
+		return g, errors.New("cannot project the vectors once they are released")
+	}
+
+	low, high := options.Level, options.Level
+
+	if options.Level == AllLevels {
+		low, high = 0, maxLevel
+	}
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	l := &h.NodeList
+
+	selected := make([]bool, len(l.Nodes))
+	count := 0
+
+	if options.MaxNodes > 0 {
+
+		// Breadth first from the entry point, over the links of the exported levels
+		next := []uint32{uint32(ep)}
+		selected[ep] = true
+		count++
+
+		for len(next) > 0 && count < options.MaxNodes {
+
+			id := next[0]
+			next = next[1:]
+
+			for level := high; level >= low && count < options.MaxNodes; level-- {
+
+				for _, link := range l.links(id, level) {
+
+					if int(link) < len(selected) && !selected[link] && count < options.MaxNodes {
+						selected[link] = true
+						count++
+						next = append(next, link)
+					}
+
+				}
+
+			}
+
+		}
+
+	} else {
+
+		for i := range l.Nodes {
+			if l.Nodes[i].Layer >= low {
+				selected[i] = true
+			}
+		}
+
+	}
+
+	for i := range l.Nodes {
+
+		if !selected[i] {
+			continue
+		}
+
+		id := uint32(i)
+
+		g.nodes = append(g.nodes, exportNode{id: id, level: l.Nodes[i].Layer, degree: len(l.links(id, low)), deleted: l.Nodes[i].Deleted})
+
+		for level := low; level <= min(high, l.Nodes[i].Layer); level++ {
+
+			for _, link := range l.links(id, level) {
+
+				if int(link) < len(selected) && selected[link] {
+					g.edges = append(g.edges, exportEdge{source: id, target: link, level: level})
+				}
+
+			}
+
+		}
+
+	}
+
+	if options.Projection {
+
+		vecs := make([][]float32, len(g.nodes))
+
+		for i, node := range g.nodes {
+			vecs[i] = h.Vector(node.id)
+		}
+
+		xs, ys := project(vecs)
+
+		for i := range g.nodes {
+			g.nodes[i].x, g.nodes[i].y = xs[i], ys[i]
+		}
+
+	}
+
+	return
+
+}
+
+// Projection of `vecs` on their first two principal components, found by power iteration
+func project(vecs [][]float32) (xs []float64, ys []float64) {
+
+	xs, ys = make([]float64, len(vecs)), make([]float64, len(vecs))
+
+	if len(vecs) == 0 {
+		return
+	}
+
+	dim := len(vecs[0])
+	mean := make([]float64, dim)
+
+	for _, v := range vecs {
+		for j := range v {
+			mean[j] += float64(v[j]) / float64(len(vecs))
+		}
+	}
+
+	centered := func(i int, j int) float64 {
+		return float64(vecs[i][j]) - mean[j]
+	}
+
+	components := [][]float64{}
+
+	for c := 0; c < 2; c++ {
+
+		// Deterministic start, not orthogonal to the components found so far
+		component := make([]float64, dim)
+
+		for j := range component {
+			component[j] = 1 / math.Sqrt(float64(dim)+float64(j*c))
+		}
+
+		for iteration := 0; iteration < 50; iteration++ {
+
+			next := make([]float64, dim)
+
+			// Covariance times the component, without forming the covariance matrix
+			for i := range vecs {
+
+				dot := 0.0
+
+				for j := 0; j < dim; j++ {
+					dot += centered(i, j) * component[j]
+				}
+
+				for j := 0; j < dim; j++ {
+					next[j] += dot * centered(i, j)
+				}
+
+			}
+
+			// Deflate, remove the components already found
+			for _, found := range components {
+
+				dot := 0.0
+
+				for j := range next {
+					dot += next[j] * found[j]
+				}
+
+				for j := range next {
+					next[j] -= dot * found[j]
+				}
+
+			}
+
+			norm := 0.0
+
+			for _, value := range next {
+				norm += value * value
+			}
+
+			if norm == 0 {
+				break
+			}
+
+			norm = math.Sqrt(norm)
+
+			for j := range next {
+				component[j] = next[j] / norm
+			}
+
+		}
+
+		components = append(components, component)
+
+	}
+
+	for i := range vecs {
+
+		for j := 0; j < dim; j++ {
+			xs[i] += centered(i, j) * components[0][j]
+			ys[i] += centered(i, j) * components[1][j]
+		}
+
+	}
+
+	return
+
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
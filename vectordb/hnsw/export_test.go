@@ -0,0 +1,141 @@
+package hnsw_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/stretchr/testify/assert"
+)
+
+// GraphML subset read back by the test
+type graphML struct {
+	Nodes []struct {
+		Id   string `xml:"id,attr"`
+		Data []struct {
+			Key   string `xml:"key,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"data"`
+	} `xml:"graph>node"`
+	Edges []struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+	} `xml:"graph>edge"`
+}
+
+func Test_Export(t *testing.T) {
+
+	// Points on a plane in 16 dimensions, the projection keeps their distances
+	vecs := make([][]float32, 300)
+
+	for i := range vecs {
+
+		a, b := rand.Float32(), rand.Float32()
+		vecs[i] = make([]float32, 16)
+
+		for j := range vecs[i] {
+			vecs[i][j] = 1 + a*float32(j%2) + b*float32(1-j%2)
+		}
+
+	}
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, h.Delete(5))
+
+	// DOT, every node and link of level 0
+	buf := bytes.Buffer{}
+	nodes, edges, err := h.ExportDOT(&buf, hnsw.ExportOptions{Level: 0})
+	assert.Nil(t, err)
+
+	dot := buf.String()
+	links := 0
+
+	for i := range h.NodeList.Nodes {
+		links += len(h.PeekNode(i).Connections[0])
+	}
+
+	assert.True(t, strings.HasPrefix(dot, "digraph hnsw {\n"))
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+	assert.Equal(t, links, strings.Count(dot, "->"))
+	assert.Equal(t, len(h.NodeList.Nodes), nodes)
+	assert.Equal(t, links, edges)
+	assert.Contains(t, dot, "  5 [level="+strconv.Itoa(h.NodeList.Nodes[5].Layer)+", degree="+strconv.Itoa(len(h.PeekNode(5).Connections[0]))+", deleted=true];")
+
+	// GraphML, sampled from the entry point with the projection
+	buf.Reset()
+	nodes, edges, err = h.ExportGraphML(&buf, hnsw.ExportOptions{Level: hnsw.AllLevels, MaxNodes: 50, Projection: true})
+	assert.Nil(t, err)
+
+	g := graphML{}
+	assert.Nil(t, xml.Unmarshal(buf.Bytes(), &g))
+
+	assert.Equal(t, 50, len(g.Nodes))
+	assert.Greater(t, len(g.Edges), 50)
+	assert.Equal(t, len(g.Nodes), nodes)
+	assert.Equal(t, len(g.Edges), edges)
+	assert.Contains(t, buf.String(), `<node id="n`+strconv.Itoa(int(h.Ep))+`">`)
+
+	// CSV edge list of level 1
+	buf.Reset()
+	nodes, edges, err = h.ExportEdgeList(&buf, hnsw.ExportOptions{Level: 1, Projection: true})
+	assert.Nil(t, err)
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"source", "target", "level", "source_level", "source_degree", "target_level", "target_degree", "source_x", "source_y", "target_x", "target_y"}, rows[0])
+
+	links = 0
+
+	for i := range h.NodeList.Nodes {
+		if h.NodeList.Nodes[i].Layer >= 1 {
+			links += len(h.PeekNode(i).Connections[1])
+		}
+	}
+
+	assert.Equal(t, links, len(rows)-1)
+	assert.Equal(t, links, edges)
+
+	linked := map[string]bool{}
+
+	for _, row := range rows[1:] {
+
+		linked[row[0]], linked[row[1]] = true, true
+
+		source, _ := strconv.Atoi(row[0])
+		target, _ := strconv.Atoi(row[1])
+
+		assert.Equal(t, "1", row[2])
+		assert.GreaterOrEqual(t, h.NodeList.Nodes[source].Layer, 1)
+		assert.GreaterOrEqual(t, h.NodeList.Nodes[target].Layer, 1)
+
+		coords := make([]float64, 4)
+
+		for i := range coords {
+			coords[i], _ = strconv.ParseFloat(row[7+i], 64)
+		}
+
+		projected := math.Hypot(coords[0]-coords[2], coords[1]-coords[3])
+		assert.InDelta(t, math.Sqrt(float64(distanceL2(vecs[source-1], vecs[target-1]))), projected, 1e-3)
+
+	}
+
+	assert.Equal(t, len(linked), nodes)
+
+	_, _, err = h.ExportDOT(&buf, hnsw.ExportOptions{Level: h.Maxlevel + 1})
+	assert.NotNil(t, err)
+
+}
@@ -1,32 +1,47 @@
 package hnsw
 
 import (
-	"container/heap"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"os"
 	"runtime"
 	"sync"
+	"time"
 
 	"log"
 
 	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/quantization"
 	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
-	"github.com/willf/bitset"
 )
 
+// Vectors and level 0 connections are held in the NodeList arenas, they are only set in nodes returned by PeekNode and
+// Snapshot
 type Node struct {
 	Connections [][]uint32 // Links to other nodes
 	Vectors     []float32  // Vector (X dimensions)
+	Half        []uint16   // Vector stored as float16 or bfloat16 (HNSW.VectorType), Vectors is then nil
+	Codes       []uint8    // Quantized vector, used for graph traversal during search if quantization is enabled
 	Layer       int        // Layer the node exists in the HNSW tree
 	Id          uint32     // Unique identifier
+	Deleted     bool       // Marked deleted, still traversed but excluded from results (see Delete)
+	Tenant      uint32     // Owner of the node for SearchTenant, 0 if inserted without a tenant
 }
 
 type NodeList struct {
 	Nodes []Node
 	mutex sync.RWMutex // Maintain a mutex for safe read/write access
+
+	dim     int
+	vectors []float32 // Vector of node i at [i*dim:(i+1)*dim]
+	half    []uint16  // As vectors, when stored as float16 or bfloat16
+	stride  int       // Mmax0 + 1
+	links0  []uint32  // Level 0 links of node i at [i*stride:(i+1)*stride], the count followed by up to Mmax0 ids
+
+	tenants map[uint32]*tenant // Nodes of each tenant, see InsertTenant
 }
 
 type HNSW_Meta struct {
@@ -40,6 +55,16 @@ type HNSW_Meta struct {
 	Maxlevel int // Track the current max level used
 
 	Heuristic bool
+
+	Seq uint64 // Number of inserts contained in the saved index
+
+	Quantization     Quantization
+	ScalarQuantizer  *quantization.ScalarQuantizer
+	ProductQuantizer *quantization.ProductQuantizer
+	BinaryQuantizer  *quantization.BinaryQuantizer
+	RerankMetric     Metric
+	VectorsReleased  bool
+	VectorType       VectorType
 }
 
 type HNSW struct {
@@ -54,10 +79,28 @@ type HNSW struct {
 
 	Heuristic bool
 
+	VectorType VectorType // Precision of the stored vectors
+
+	Quantization     Quantization                   // Vector quantization used to traverse the graph during search
+	ScalarQuantizer  *quantization.ScalarQuantizer  // Trained int8 quantizer (QuantizationInt8)
+	ProductQuantizer *quantization.ProductQuantizer // Trained PQ codebooks (QuantizationPQ)
+	BinaryQuantizer  *quantization.BinaryQuantizer  // Per dimension thresholds (QuantizationBinary)
+	RerankMetric     Metric                         // Distance used to rerank the quantized search candidates
+
+	TenantBruteForce int // SearchTenant scans the nodes of tenants with up to this many vectors instead of the graph, sparse tenants are also scanned (see SearchTenant)
+
+	VectorsReleased bool        // Full precision vectors are no longer held in memory, see ReleaseVectors
+	vectorStore     VectorStore // Source of the full precision vectors to rerank once released
+
+	metrics Metrics // Inserts and searches are reported if set, see WithMetrics
+
 	NodeList NodeList // Used to store the vectors within each node
 
-	mutex sync.RWMutex
-	Wg    sync.WaitGroup
+	seq uint64 // Sequence number of the last insert, assigned in node Id order
+
+	mutex     sync.RWMutex
+	writeGate sync.RWMutex // Held shared by each insert, exclusively by Snapshot to pause writers
+	Wg        sync.WaitGroup
 }
 
 type SearchQuery struct {
@@ -67,7 +110,8 @@ type SearchQuery struct {
 
 type SearchResults struct {
 	Id             int
-	BestCandidates queue.PriorityQueue
+	BestCandidates queue.TopK
+	Latency        time.Duration // Time taken to search the query
 }
 
 // Set defaults for HNSW
@@ -76,10 +120,11 @@ const M = 16
 const Mmax = M
 const Mmax0 = M * 2
 const Efconstruction = 200 // Can be auto-configured using sample data
+const TenantBruteForce = 1000
 
 const Version = 1.0
 
-func New(m int, mmax int, mmax0 int, efconstruction int, vecsize int) (h HNSW, err error) {
+func New(m int, mmax int, mmax0 int, efconstruction int, vecsize int, options ...Option) (h HNSW, err error) {
 
 	h.M = m
 	h.Mmax = mmax
@@ -97,13 +142,31 @@ func New(m int, mmax int, mmax0 int, efconstruction int, vecsize int) (h HNSW, e
 	// on different layers to keep it small to reduce the average number of hops in a greedy search on each layer.
 	h.Ml = 1 / math.Log(1.0*float64(h.M))
 
+	h.TenantBruteForce = TenantBruteForce
+
+	// Sized before the options, which check training samples against the vector size
+	h.NodeList.init(vecsize, h.Mmax0)
+
+	for _, option := range options {
+
+		err = option(&h)
+
+		if err != nil {
+			return
+		}
+
+	}
+
 	// Populate our first node
-	h.NodeList.Nodes = make([]Node, 1)
 
 	// Required to create the first node and entry-point (TODO revise to use first element of the import, vs using null vector)
-	h.NodeList.Nodes[0] = Node{Id: 0, Layer: 0, Vectors: make([]float32, vecsize), Connections: make([][]uint32, h.Mmax0+1)}
+	node := Node{Id: 0, Layer: 0, Connections: make([][]uint32, h.Mmax0+1)}
+	h.storeVector(&node, make([]float32, vecsize))
+	node.Codes = h.encode(make([]float32, vecsize))
 
-	return h, nil
+	h.NodeList.append(node)
+
+	return
 
 }
 
@@ -111,38 +174,58 @@ func New(m int, mmax int, mmax0 int, efconstruction int, vecsize int) (h HNSW, e
 // Output: update h inserting element q
 func (h *HNSW) Insert(q []float32) (uint32, error) {
 
+	return h.insert(q, 0)
+
+}
+
+// Insert `q` owned by `tenant` (0 for no tenant)
+func (h *HNSW) insert(q []float32, tenant uint32) (uint32, error) {
+
 	var err error
-	node := Node{}
+	node := Node{Tenant: tenant}
+
+	var start time.Time
+
+	if h.metrics != nil {
+		start = time.Now()
+	}
+
+	// Block while a snapshot is being taken, Snapshot waits for in-flight inserts to complete
+	h.writeGate.RLock()
+	defer h.writeGate.RUnlock()
+
+	if h.VectorsReleased {
+		return 0, errors.New("cannot insert once vectors are released")
+	}
+
+	err = h.checkDimension(q)
+
+	if err != nil {
+		return 0, err
+	}
 
-	// TODO: Confirm performance difference
-	node.Vectors = make([]float32, len(q))
-	node.Vectors = q
+	h.storeVector(&node, q)
+	node.Codes = h.encode(q)
 
 	// Current distance from our starting-point (ep)
 	currentObj := &h.NodeList.Nodes[h.Ep]
-	currentDist, err := distance.L2_Opt(&currentObj.Vectors, &q)
+	currentDist := h.distanceTo(&q, currentObj.Id)
 
 	h.NodeList.mutex.Lock()
 
 	// Generate the new layer
 	node.Layer = int(math.Floor(-math.Log(rand.Float64()) * h.Ml))
 	node.Id = uint32(len(h.NodeList.Nodes))
+	h.seq++
 
 	// Create connections
 	node.Connections = make([][]uint32, h.M+1)
 
 	// Append new node
-	h.NodeList.Nodes = append(h.NodeList.Nodes, node)
+	h.NodeList.append(node)
 
 	h.NodeList.mutex.Unlock()
 
-	ep := &queue.PriorityQueue{}
-	ep.Order = false
-	heap.Init(ep)
-
-	var topCandidates queue.PriorityQueue
-	topCandidates.Order = false
-
 	// Find single shortest path from top layers above our current node, which will be our new starting-point
 
 	for level := currentObj.Layer; level > node.Layer; level-- {
@@ -155,11 +238,7 @@ func (h *HNSW) Insert(q []float32) (uint32, error) {
 			// TODO: Must return the connections from our Ep to this specific level, otherwise will traverse the entire level which is inefficient
 			for _, nodeId := range h.GetConnections(currentObj, level) {
 
-				nodeDist, err := distance.L2_Opt(&h.NodeList.Nodes[nodeId].Vectors, &q)
-
-				if err != nil {
-					log.Fatal(err)
-				}
+				nodeDist := h.distanceTo(&q, nodeId)
 
 				if nodeDist < currentDist {
 
@@ -179,13 +258,17 @@ func (h *HNSW) Insert(q []float32) (uint32, error) {
 
 	}
 
-	// Update our min-heap for the winning node
-	heap.Push(ep, &queue.Item{Distance: currentDist, Node: currentObj.Id})
+	// Scratch space for each layer search, the candidates are consumed before the context is returned
+	ctx := getSearchContext(len(h.NodeList.Nodes))
+	defer putSearchContext(ctx)
+
+	topCandidates := &ctx.top
+	dist := h.exactDistance(&q)
 
 	// For all levels equal and below our current node, find the top (closest) candidates and create a link
 	for level := min(int(node.Layer), int(h.Maxlevel)); level >= 0; level-- {
 
-		err = h.SearchLayer(&q, &queue.Item{Distance: currentDist, Node: currentObj.Id}, &topCandidates, int(h.Efconstruction), uint(level))
+		err = h.searchLayer(ctx, dist, queue.Item{Node: currentObj.Id, Distance: currentDist}, topCandidates, int(h.Efconstruction), uint(level))
 
 		if err != nil {
 			log.Fatal(err)
@@ -197,18 +280,19 @@ func (h *HNSW) Insert(q []float32) (uint32, error) {
 		case false:
 
 			// TODO: Confirm h.Max or h.Max0?
-			h.SelectNeighboursSimple(&topCandidates, int(h.M))
+			h.SelectNeighboursSimple(topCandidates, int(h.M))
 
 		case true:
-			// Select by heurisitc, using max-heap
-			h.SelectNeighboursHeuristic(&topCandidates, int(h.M), false)
+			// Select by heurisitc, nearest candidates first
+			h.SelectNeighboursHeuristic(topCandidates, int(h.M))
 
 		}
 
 		node.Connections[level] = make([]uint32, topCandidates.Len())
 
+		// Popped furthest first, order by best performing match (index 0) .. lowest
 		for i := topCandidates.Len() - 1; i >= 0; i-- {
-			candidate := heap.Pop(&topCandidates).(*queue.Item)
+			candidate, _ := topCandidates.Pop()
 			//fmt.Printf("Adding node.Connections[%d][%d] = %d\n", level, i, candidate.Node)
 			node.Connections[level][i] = candidate.Node
 		}
@@ -217,14 +301,16 @@ func (h *HNSW) Insert(q []float32) (uint32, error) {
 
 	// Append our new connections
 	h.NodeList.mutex.Lock()
-	h.NodeList.Nodes[node.Id].Connections = node.Connections
+	for level := range node.Connections {
+		h.NodeList.setLinks(node.Id, level, node.Connections[level])
+	}
 	h.NodeList.mutex.Unlock()
 
 	// Next link the neighbour nodes to our new node, making it visible
 	for level := min(int(node.Layer), int(h.Maxlevel)); level >= 0; level-- {
 
 		h.NodeList.mutex.Lock()
-		for _, neighbourNode := range h.NodeList.Nodes[node.Id].Connections[level] {
+		for _, neighbourNode := range node.Connections[level] {
 			h.AddConnections(neighbourNode, node.Id, level)
 		}
 		h.NodeList.mutex.Unlock()
@@ -240,6 +326,10 @@ func (h *HNSW) Insert(q []float32) (uint32, error) {
 		h.mutex.Unlock()
 	}
 
+	if h.metrics != nil {
+		h.metrics.ObserveInsert(time.Since(start))
+	}
+
 	return node.Id, nil
 }
 
@@ -296,170 +386,152 @@ func (h *HNSW) AddConnections(neighbourNode uint32, newNode uint32, level int) {
 		maxConnections = int(h.Mmax)
 	}
 
-	// Add a min-heap
-	h.NodeList.Nodes[neighbourNode].Connections[level] = append(h.NodeList.Nodes[neighbourNode].Connections[level], newNode)
-
-	currentConnections := len(h.NodeList.Nodes[neighbourNode].Connections[level])
-
-	if currentConnections > maxConnections {
-
-		switch h.Heuristic {
-
-		case false:
-			// Add the new candidate to our queue
-			topCandidates := &queue.PriorityQueue{}
-			topCandidates.Order = true // min-heap, set to true for max-heap
-			heap.Init(topCandidates)
+	// Add a min-heap, level 0 links are a view of the arena so append always copies
+	connections := append(h.NodeList.links(neighbourNode, level), newNode)
 
-			// Loop through each current connection and add the the max-heap
-			for i := 0; i < currentConnections; i++ {
-				connectedNode := h.NodeList.Nodes[neighbourNode].Connections[level][i]
-				distanceBetweenNodes, err := distance.L2_Opt(&h.NodeList.Nodes[neighbourNode].Vectors, &h.NodeList.Nodes[connectedNode].Vectors)
+	currentConnections := len(connections)
 
-				if err != nil {
-					log.Fatal(err)
-				}
+	if currentConnections <= maxConnections {
+		h.NodeList.setLinks(neighbourNode, level, connections)
+		return
+	}
 
-				heap.Push(topCandidates, &queue.Item{Node: connectedNode, Distance: distanceBetweenNodes})
-			}
+	// Add each current connection to a max-heap, the furthest on top
+	topCandidates := queue.NewMaxHeap(currentConnections)
 
-			// Next, prune the weaker links, we want the best performing
-			h.SelectNeighboursSimple(topCandidates, maxConnections)
+	for i := 0; i < currentConnections; i++ {
+		connectedNode := connections[i]
+		distanceBetweenNodes := h.distanceBetween(neighbourNode, connectedNode)
 
-			// Next, reorder our connected nodes with the improved lower distances within the graph
+		topCandidates.Push(queue.Item{Node: connectedNode, Distance: distanceBetweenNodes})
+	}
 
-			h.NodeList.Nodes[neighbourNode].Connections[level] = make([]uint32, maxConnections)
+	// Next, prune the weaker links, we want the best performing. The heuristic is not applied when pruning (the new
+	// node was already selected by it), either way the nearest links are kept
+	h.SelectNeighboursSimple(topCandidates, maxConnections)
 
-			// Order by best performing match (index 0) .. lowest
-			for i := maxConnections - 1; i >= 0; i-- {
-				node := heap.Pop(topCandidates).(*queue.Item)
-				h.NodeList.Nodes[neighbourNode].Connections[level][i] = node.Node
-			}
+	pruned := make([]uint32, maxConnections)
 
-		case true:
+	// Order by best performing match (index 0) .. lowest
+	for i := maxConnections - 1; i >= 0; i-- {
+		node, _ := topCandidates.Pop()
+		pruned[i] = node.Node
+	}
 
-			// Add the new candidate to our queue
-			topCandidates := &queue.PriorityQueue{}
-			topCandidates.Order = false // min-heap, set to true for max-heap
-			heap.Init(topCandidates)
+	// Next, reorder our connected nodes with the improved lower distances within the graph
+	h.NodeList.setLinks(neighbourNode, level, pruned)
 
-			// Loop through each current connection and add the the max-heap
-			for i := 0; i < currentConnections; i++ {
-				connectedNode := h.NodeList.Nodes[neighbourNode].Connections[level][i]
-				distanceBetweenNodes, err := distance.L2_Opt(&h.NodeList.Nodes[neighbourNode].Vectors, &h.NodeList.Nodes[connectedNode].Vectors)
+}
 
-				if err != nil {
-					log.Fatal(err)
-				}
+// Get links for a desired entry-point (ep) at a specified layer in the HNSW graph.
+func (h *HNSW) GetConnections(ep *Node, level int) []uint32 {
 
-				item := &queue.Item{Node: connectedNode, Distance: distanceBetweenNodes}
-				heap.Push(topCandidates, item)
+	// Level 0 is held in the arena, see NodeList.links
+	return h.NodeList.links(ep.Id, level)
 
-				//fmt.Printf("\tCurrent connections, node %d, distance %f\n", item.Node, item.Distance)
-			}
+}
 
-			// Next, prune the weaker links, we want the best performing
-			h.SelectNeighboursSimple(topCandidates, maxConnections)
-			//h.SelectNeighboursHeuristic(topCandidates, maxConnections, true)
+// Input: Query element `q`, enter point `ep`, `M` number of nearest to `q“ elements to return, layer number `layerNum`
+// Output: `nearestElements` closest neighbours to `q`
+func (h *HNSW) SearchLayer(q *[]float32, ep queue.Item, topCandidates *queue.MaxHeap, ef int, level uint) (err error) {
 
-			// Next, reorder our connected nodes with the improved lower distances within the graph
-			h.NodeList.Nodes[neighbourNode].Connections[level] = make([]uint32, maxConnections)
+	err = h.checkDimension(*q)
 
-			// Order by best performing match (index 0) .. lowest
-			for i := 0; i < maxConnections; i++ {
-				node := heap.Pop(topCandidates).(*queue.Item)
-				h.NodeList.Nodes[neighbourNode].Connections[level][i] = node.Node
-			}
+	if err != nil {
+		return err
+	}
 
-		}
+	ctx := getSearchContext(len(h.NodeList.Nodes))
+	defer putSearchContext(ctx)
 
-	}
+	return h.searchLayer(ctx, h.exactDistance(q), ep, topCandidates, ef, level)
 
 }
 
-// Get links for a desired entry-point (ep) at a specified layer in the HNSW graph.
-func (h *HNSW) GetConnections(ep *Node, level int) []uint32 {
+// Search a layer using `dist` for the distance from the query to each node, see queryDistance. The candidates heap and
+// visited list of `ctx` are used as scratch space
+func (h *HNSW) searchLayer(ctx *searchContext, dist queryDistance, ep queue.Item, topCandidates *queue.MaxHeap, ef int, level uint) (err error) {
 
-	// Return pointer for performance?
-	return ep.Connections[level]
+	// Visited nodes are tagged with the current epoch, no clearing required
+	visited := &ctx.visited
+	visited.reset()
 
-}
-
-// Input: Query element `q`, enter point `ep`, `M` number of nearest to `q“ elements to return, layer number `layerNum`
-// Output: `nearestElements` closest neighbours to `q`
-func (h *HNSW) SearchLayer(q *[]float32, ep *queue.Item, topCandidates *queue.PriorityQueue, ef int, level uint) (err error) {
+	// The entry point is already a candidate, if revisited it would be added to topCandidates twice
+	visited.visit(ep.Node)
 
-	// TODO: Optimise
-	//visited := make(map[uint32]bool)
-	var visited bitset.BitSet
+	// Add the new candidate to our queue (min-heap)
+	candidates := &ctx.candidates
+	candidates.Reset()
+	candidates.Push(ep)
 
-	// Add the new candidate to our queue
-	candidates := &queue.PriorityQueue{}
-	candidates.Order = false // min-heap, set to true for max-heap
-	heap.Init(candidates)
-	heap.Push(candidates, ep)
+	// Init our topCandidates max-heap, first record worst distance
+	topCandidates.Push(ep)
 
-	// Init our topCandidates min-heap, first record worst distance
-	topCandidates.Order = true // max-heap
-	heap.Init(topCandidates)
-	heap.Push(topCandidates, ep)
+	// Recorded by SearchTrace only
+	trace := ctx.trace
+	var expansion *TraceExpansion
 
 	for candidates.Len() > 0 {
 
-		lowerBound := topCandidates.Top().(*queue.Item).Distance
+		lowerBound, _ := topCandidates.Top()
 
-		candidate := heap.Pop(candidates).(*queue.Item)
+		candidate, _ := candidates.Pop()
+
+		if candidate.Distance > lowerBound.Distance {
+
+			if trace != nil {
+				trace.Stop = &TraceStop{Node: candidate.Node, Distance: candidate.Distance, LowerBound: lowerBound.Distance}
+			}
 
-		if candidate.Distance > lowerBound {
 			break
 		}
 
 		// Loop through each element in our nodes connections
 		// TODO: Optimise loop, only add levels to connections if used, vs allocting all
+		links := h.NodeList.links(candidate.Node, int(level))
+
+		if trace != nil {
+			trace.Expansions = append(trace.Expansions, TraceExpansion{Node: candidate.Node, Distance: candidate.Distance, LowerBound: lowerBound.Distance, Links: len(links), Evaluated: []TraceNeighbour{}})
+			expansion = &trace.Expansions[len(trace.Expansions)-1]
+		}
 
 		//h.NodeList.mutex.RLock()
-		for _, node := range h.NodeList.Nodes[candidate.Node].Connections[level] {
+		for _, node := range links {
 
 			// If the node is not yet visited
-			//if !visited[node] {
-			if !visited.Test(uint(node)) {
-				visited.Set(uint(node))
-				//visited[node] = true
+			if !visited.visit(node) {
 
-				nodeDist, err := distance.L2_Opt(q, &h.NodeList.Nodes[node].Vectors)
+				nodeDist := dist(node)
 
-				// Required?
-				if err != nil {
-					log.Fatal(err)
-				}
+				item := queue.Item{Node: node, Distance: nodeDist}
 
-				item := &queue.Item{
-					Distance: nodeDist,
-					Node:     node,
-				}
+				top, _ := topCandidates.Top()
 
-				topDistance := topCandidates.Top().(*queue.Item).Distance
+				accepted := true
 
 				// Add the element to topCandidates if size < efConstruction
 				if topCandidates.Len() < ef {
 
-					if node != ep.Node {
-						heap.Push(topCandidates, item)
-					}
+					topCandidates.Push(item)
 
 					// Add our new node to our list of candidates to search
-					heap.Push(candidates, item)
+					candidates.Push(item)
 
-				} else if topDistance > nodeDist {
+				} else if top.Distance > nodeDist {
 
-					heap.Push(topCandidates, item)
-
-					// Remove the worst performing
-					heap.Pop(topCandidates)
+					// Replace the worst performing
+					topCandidates.PushBounded(item, ef)
 
 					// Add our new node to our list of candidates to search
-					heap.Push(candidates, item)
+					candidates.Push(item)
+
+				} else {
+					accepted = false
+				}
 
+				if trace != nil {
+					expansion.Evaluated = append(expansion.Evaluated, TraceNeighbour{Node: node, Distance: nodeDist, Accepted: accepted, Bound: top.Distance})
+					trace.Visited++
 				}
 
 			}
@@ -475,10 +547,10 @@ func (h *HNSW) SearchLayer(q *[]float32, ep *queue.Item, topCandidates *queue.Pr
 
 // Input: Candidate elements `C`, number of neighbours to return `M`
 // Output: `M` nearest elements in heap
-func (h *HNSW) SelectNeighboursSimple(topCandidates *queue.PriorityQueue, M int) {
+func (h *HNSW) SelectNeighboursSimple(topCandidates *queue.MaxHeap, M int) {
 
 	for topCandidates.Len() > M {
-		_ = heap.Pop(topCandidates).(*queue.Item)
+		topCandidates.Pop()
 	}
 
 }
@@ -486,63 +558,36 @@ func (h *HNSW) SelectNeighboursSimple(topCandidates *queue.PriorityQueue, M int)
 // Input: base element q, candidate elements C, number of neighbors to return M, layer number lc, flag indicating whether or not to extend candidate list extendCandidates, flag indicating whether or not to add discarded elements keepPrunedConnections
 // Output: M elements selected by the heuristic
 
-func (h *HNSW) SelectNeighboursHeuristic(topCandidates *queue.PriorityQueue, M int, order bool) {
+func (h *HNSW) SelectNeighboursHeuristic(topCandidates *queue.MaxHeap, M int) {
 
 	// If results < M, return, nothing required
 	if topCandidates.Len() < M {
 		return
 	}
 
-	// Create our new priority queues
-	newCandidates := &queue.PriorityQueue{}
-
-	tmpCandidates := queue.PriorityQueue{}
-	tmpCandidates.Order = order // min-heap, set to true for max-heap
-	heap.Init(&tmpCandidates)
+	// Scan the candidates nearest first
+	candidates := topCandidates.Drain(make([]queue.Item, 0, topCandidates.Len()))
 
-	items := make([]*queue.Item, 0, M)
-
-	if !order {
-
-		newCandidates.Order = order // min-heap, set to true for max-heap
-		heap.Init(newCandidates)
-
-		// Add existing candidates to our new queue
-		for topCandidates.Len() > 0 {
-
-			item := heap.Pop(topCandidates).(*queue.Item)
-
-			//fmt.Printf("Pushing newCandidates, node (%d), distance (%f)\n", item.Node, item.Distance)
-			heap.Push(newCandidates, item)
-			//fmt.Printf("\t item %d, distance (%f)\n", item.Node, item.Distance)
-		}
-
-	} else {
-		newCandidates = topCandidates
-	}
+	items := make([]queue.Item, 0, M)
 
-	//fmt.Println()
+	// Discarded candidates, nearest first
+	var discarded []queue.Item
 
-	// Scan through our new queue (order changed from min-heap > max-heap or vice-versa depending on order arg)
-	for newCandidates.Len() > 0 {
+	for _, item := range candidates {
 
 		// Finish if items reaches our desired length
 		if len(items) >= M {
-			//fmt.Printf("Breaking %d > %d\n", len(items), M)
 			break
 		}
 
-		item := heap.Pop(newCandidates).(*queue.Item)
-
 		hit := true
 
 		// Search through each item and determine if distance from node lower for items in set
 		for _, v := range items {
 
-			nodeDist, _ := distance.L2_Opt(&h.NodeList.Nodes[v.Node].Vectors, &h.NodeList.Nodes[item.Node].Vectors)
+			nodeDist := h.distanceBetween(v.Node, item.Node)
 
 			if nodeDist < item.Distance {
-				//fmt.Printf("SelectNeighboursHeuristic, node %d to candidate %d distance (%f), lower than original %f\n", v.Node, item.Node, nodeDist, item.Distance)
 				hit = false
 				break
 			}
@@ -552,27 +597,24 @@ func (h *HNSW) SelectNeighboursHeuristic(topCandidates *queue.PriorityQueue, M i
 		if hit {
 			items = append(items, item)
 		} else {
-			heap.Push(&tmpCandidates, item)
+			discarded = append(discarded, item)
 		}
 
 	}
 
-	// Add any additional items from tmpCandidates if current items < M
-	for len(items) < M && tmpCandidates.Len() > 0 {
-		//fmt.Printf("SelectNeighboursHeuristic, items len (%d), tmpCandidates len %d, appending item from tmpCandidates\n", len(items), tmpCandidates.Len())
-
-		item := heap.Pop(&tmpCandidates).(*queue.Item)
-		items = append(items, item)
+	// Add any additional items from the discarded candidates if current items < M
+	for i := 0; len(items) < M && i < len(discarded); i++ {
+		items = append(items, discarded[i])
 	}
 
-	// Last step, append our results into our original min/max-heap
+	// Last step, append our results into our original max-heap
 	for _, item := range items {
-		heap.Push(topCandidates, item)
+		topCandidates.Push(item)
 	}
 
 }
 
-func (h *HNSW) LegacySearchLayer(q *[]float32, ep *[]float32, C *[]uint32, M int) queue.PriorityQueue {
+func (h *HNSW) LegacySearchLayer(q *[]float32, ep *[]float32, C *[]uint32, M int) queue.MinHeap {
 
 	var lowerBound float32
 
@@ -583,39 +625,32 @@ func (h *HNSW) LegacySearchLayer(q *[]float32, ep *[]float32, C *[]uint32, M int
 		log.Fatal(err)
 	}
 
-	topCandidates := &queue.PriorityQueue{}
-
-	topCandidates.Order = false // min-heap, set to true for max-heap
-	heap.Init(topCandidates)
+	topCandidates := &queue.MinHeap{}
 
 	var id int
 
 	for _, node := range *C {
 
-		nodeDist, err := distance.L2_Opt(q, &h.NodeList.Nodes[node].Vectors)
-
-		if err != nil {
-			log.Fatal(err)
-		}
+		nodeDist := h.distanceTo(q, node)
 
 		if topCandidates.Len() < M || lowerBound > nodeDist {
 
-			candidate := &queue.Item{
+			candidate := queue.Item{
 				Distance: nodeDist,
 				Node:     node,
 			}
 
 			// Add the new candidate to our queue
-			heap.Push(topCandidates, candidate)
+			topCandidates.Push(candidate)
 
 			// If number of candidates > efConstruction, pop the smallest distance out
 			if topCandidates.Len() > int(h.Efconstruction) {
-				heap.Pop(topCandidates)
+				topCandidates.Pop()
 			}
 
 			// Update the lowerBound to our min value in the heap
-			if topCandidates.Len() > 0 {
-				lowerBound = topCandidates.Items[0].Distance
+			if top, ok := topCandidates.Top(); ok {
+				lowerBound = top.Distance
 			}
 
 			id++
@@ -636,27 +671,12 @@ func (h *HNSW) KnnSearch(q Node, K int, ef int) (nearestElements []Node) {
 
 }
 
-// Find query point `q` and result `K` results (max-heap)
-func (h *HNSW) Search(q *[]float32, topCandidates *queue.PriorityQueue, K int, efSearch int) (err error) {
+// Find the nearest nodes to query point `q`, collected in `results` (up to results.K() nodes). Existing results are
+// kept if nearer, e.g when merging the results of several indexes
+func (h *HNSW) Search(q *[]float32, results *queue.TopK, efSearch int) (err error) {
 
-	currentObj := &h.NodeList.Nodes[h.Ep]
-	match, currentDist, err := h.FindEp(q, currentObj, 0)
+	return h.search(q, results, efSearch, nil)
 
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = h.SearchLayer(q, &queue.Item{Distance: currentDist, Node: match.Id}, topCandidates, efSearch, 0)
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	for topCandidates.Len() > K {
-		_ = heap.Pop(topCandidates).(*queue.Item)
-	}
-
-	return nil
 }
 
 // Search concurrent
@@ -682,15 +702,16 @@ func (h *HNSW) SearchWorker(id int, K int, efSearch int, jobs <-chan SearchQuery
 
 	for q := range jobs {
 
-		var bestCandidates queue.PriorityQueue
-		heap.Init(&bestCandidates)
-		err := h.Search(&q.Qp, &bestCandidates, K, efSearch)
+		start := time.Now()
+
+		bestCandidates := queue.NewTopK(K)
+		err := h.Search(&q.Qp, bestCandidates, efSearch)
 
 		if err != nil {
 			return err
 		}
 
-		resultChan <- SearchResults{Id: q.Id, BestCandidates: bestCandidates}
+		resultChan <- SearchResults{Id: q.Id, BestCandidates: *bestCandidates, Latency: time.Since(start)}
 
 	}
 
@@ -700,37 +721,31 @@ func (h *HNSW) SearchWorker(id int, K int, efSearch int, jobs <-chan SearchQuery
 }
 
 // Brute search
-func (h *HNSW) BruteSearch(q *[]float32, K int) (topCandidates queue.PriorityQueue, err error) {
+func (h *HNSW) BruteSearch(q *[]float32, K int) (topCandidates queue.TopK, err error) {
 
-	topCandidates.Order = true
+	topCandidates = *queue.NewTopK(K)
 
-	h.NodeList.mutex.RLock()
+	if h.VectorsReleased {
+		return topCandidates, errors.New("brute search requires the full precision vectors, released from the index")
+	}
 
-	for i := 0; i < len(h.NodeList.Nodes); i++ {
+	err = h.checkDimension(*q)
 
-		nodeDist, err := distance.L2_Opt(q, &h.NodeList.Nodes[i].Vectors)
+	if err != nil {
+		return
+	}
 
-		if err != nil {
-			log.Fatal(err)
-		}
+	h.NodeList.mutex.RLock()
 
-		if topCandidates.Len() < K {
-			heap.Push(&topCandidates, &queue.Item{
-				Node:     uint32(i),
-				Distance: nodeDist,
-			})
+	for i := 0; i < len(h.NodeList.Nodes); i++ {
+
+		if h.NodeList.Nodes[i].Deleted {
 			continue
 		}
 
-		largestDist := topCandidates.Top().(*queue.Item)
+		nodeDist := h.distanceTo(q, uint32(i))
 
-		if nodeDist < largestDist.Distance {
-			heap.Pop(&topCandidates)
-			heap.Push(&topCandidates, &queue.Item{
-				Node:     uint32(i),
-				Distance: nodeDist,
-			})
-		}
+		topCandidates.Push(queue.Item{Node: uint32(i), Distance: nodeDist})
 
 	}
 
@@ -762,15 +777,15 @@ func (h *HNSW) BruteSearchWorker(id int, K int, jobs <-chan SearchQuery, resultC
 
 	for q := range jobs {
 
-		//var bestCandidates queue.PriorityQueue
-		//heap.Init(&bestCandidates)
+		start := time.Now()
+
 		bestCandidates, err := h.BruteSearch(&q.Qp, K)
 
 		if err != nil {
 			return err
 		}
 
-		resultChan <- SearchResults{Id: q.Id, BestCandidates: bestCandidates}
+		resultChan <- SearchResults{Id: q.Id, BestCandidates: bestCandidates, Latency: time.Since(start)}
 
 	}
 
@@ -781,7 +796,23 @@ func (h *HNSW) BruteSearchWorker(id int, K int, jobs <-chan SearchQuery, resultC
 
 func (h *HNSW) FindEp(q *[]float32, currentObj *Node, layer int16) (match Node, currentDist float32, err error) {
 
-	currentDist, err = distance.L2_Opt(q, &currentObj.Vectors)
+	err = h.checkDimension(*q)
+
+	if err != nil {
+		return
+	}
+
+	return h.findEp(h.exactDistance(q), currentObj, nil)
+
+}
+
+// Find the entry-point for layer 0 using `dist` for the distance from the query to each node, see queryDistance. Each
+// move to a nearer node is recorded in `trace` if not nil
+func (h *HNSW) findEp(dist queryDistance, currentObj *Node, trace *Trace) (match Node, currentDist float32, err error) {
+
+	// Start from the entry-point, it is the match if no closer node is found on the upper layers
+	match = *currentObj
+	currentDist = dist(currentObj.Id)
 
 	// Find single shortest path from top layers above our current node, which will be our new starting-point
 	for level := h.Maxlevel; level > 0; level-- {
@@ -795,20 +826,21 @@ func (h *HNSW) FindEp(q *[]float32, currentObj *Node, layer int16) (match Node,
 
 			for _, nodeId := range h.GetConnections(currentObj, level) {
 
-				nodeDist, err := distance.L2_Opt(&h.NodeList.Nodes[nodeId].Vectors, q)
-
-				if err != nil {
-					log.Fatal(err)
-				}
+				nodeDist := dist(nodeId)
 
 				if nodeDist < currentDist {
 
-					// Update the starting point to our new node
+					// Update the starting point to our new node, the next scan is of its connections
 					match = h.NodeList.Nodes[nodeId]
+					currentObj = &h.NodeList.Nodes[nodeId]
 
 					// Update the currently shortest distance
 					currentDist = nodeDist
 
+					if trace != nil {
+						trace.Path = append(trace.Path, TraceHop{Level: level, Node: nodeId, Distance: nodeDist})
+					}
+
 					// If a smaller match found, continue
 					scan = true
 
@@ -824,117 +856,23 @@ func (h *HNSW) FindEp(q *[]float32, currentObj *Node, layer int16) (match Node,
 
 }
 
-func (h *HNSW) Stats() {
-
-	fmt.Printf("h.M = %d\n", h.M)
-	fmt.Printf("h.Mmax = %d\n", h.Mmax)
-	fmt.Printf("h.Mmax0 = %d\n", h.Mmax0)
-	fmt.Printf("h.Efconstruction = %d\n", h.Efconstruction)
-
-	fmt.Printf("h.Ep = %d\n", h.Ep)
-	fmt.Printf("h.Maxlevel = %d\n", h.Maxlevel)
-
-	fmt.Printf("h.Heuristic = %v\n", h.Heuristic)
-
-	fmt.Printf("h.Ml = %f\n\n", h.Ml)
-
-	fmt.Printf("Number of nodes = %d\n", len(h.NodeList.Nodes))
-
-	levelStats := make([]int, h.Maxlevel+1)
-	connectionStats := make([]int, h.Maxlevel+1)
-	connectionNodeStats := make([]int, h.Maxlevel+1)
-
-	for i := 0; i < len(h.NodeList.Nodes)-1; i++ {
-		levelStats[h.NodeList.Nodes[i].Layer]++
-
-		// Loop through each connection
-		for i2 := int(h.NodeList.Nodes[i].Layer); i2 >= 0; i2-- {
-
-			if len(h.NodeList.Nodes[i].Connections[i2]) > i2 {
-				total := len(h.NodeList.Nodes[i].Connections[i2])
-				connectionStats[i2] += total
-				connectionNodeStats[i2]++
-
-			}
-
-		}
-	}
-
-	for k, v := range levelStats {
-		avg := int(connectionStats[k] / max(1, connectionNodeStats[k]))
-		fmt.Printf("\tLevel %d, number of nodes %d, number of connections %d, avg %d\n", k, v, connectionStats[k], avg)
-	}
-
-	fmt.Printf("Total number of node levels = %d\n", len(levelStats))
-
-}
-
-// Peek a node
+// Peek a node, including its vector and the links of every level (NodeList.Nodes holds the vectors and level 0 links
+// in arenas)
 func (h *HNSW) PeekNode(id int) Node {
 
-	return h.NodeList.Nodes[id]
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	return h.NodeList.node(uint32(id))
 
 }
 
+// Save a consistent snapshot of the index, safe to call while inserts are running
 func (h *HNSW) Save(filename string) (err error) {
 
-	// Dump meta-data
-	file, err := os.Create(fmt.Sprintf("%s.meta", filename))
-
-	if err != nil {
-		return err
-	}
-
-	encoder := gob.NewEncoder(file)
-
-	// Faster performance?
-	/*
-		for k, _ := range h.NodeList.Nodes {
-			err = encoder.Encode(h.NodeList.Nodes[k])
-		}
-	*/
-
-	err = encoder.Encode(HNSW_Meta{
-		Efconstruction: h.Efconstruction,
-		M:              h.M,
-		Mmax:           h.Mmax,
-		Mmax0:          h.Mmax0,
-		Ml:             h.Ml,
-		Ep:             h.Ep,
-		Maxlevel:       h.Maxlevel,
-		Heuristic:      h.Heuristic,
-	})
-
-	if err != nil {
-		return err
-	}
-
-	file.Close()
-
-	file, err = os.Create(filename)
-
-	if err != nil {
-		return err
-	}
-
-	encoder = gob.NewEncoder(file)
-
-	// Faster performance?
-	/*
-		for k, _ := range h.NodeList.Nodes {
-			err = encoder.Encode(h.NodeList.Nodes[k])
-		}
-	*/
-
-	err = encoder.Encode(h.NodeList.Nodes)
-
-	if err != nil {
-		return err
-	}
-
-	file.Close()
+	snapshot := h.Snapshot()
 
-	return
+	return snapshot.Save(filename)
 
 }
 
@@ -960,6 +898,15 @@ func Load(filename string) (h HNSW, err error) {
 	h.Ep = meta.Ep
 	h.Maxlevel = meta.Maxlevel
 	h.Heuristic = meta.Heuristic
+	h.seq = meta.Seq
+	h.Quantization = meta.Quantization
+	h.ScalarQuantizer = meta.ScalarQuantizer
+	h.ProductQuantizer = meta.ProductQuantizer
+	h.BinaryQuantizer = meta.BinaryQuantizer
+	h.RerankMetric = meta.RerankMetric
+	h.VectorsReleased = meta.VectorsReleased
+	h.VectorType = meta.VectorType
+	h.TenantBruteForce = TenantBruteForce
 
 	if err != nil {
 		return
@@ -975,7 +922,9 @@ func Load(filename string) (h HNSW, err error) {
 
 	decoder = gob.NewDecoder(file)
 
-	err = decoder.Decode(&h.NodeList.Nodes)
+	var nodes []Node
+
+	err = decoder.Decode(&nodes)
 
 	if err != nil {
 		return
@@ -983,12 +932,25 @@ func Load(filename string) (h HNSW, err error) {
 
 	file.Close()
 
+	h.NodeList.setNodes(nodes, h.Mmax0)
+
+	// Indexes saved before sequence tracking, every node after the entry-point was an insert
+	if h.seq == 0 && len(h.NodeList.Nodes) > 0 {
+		h.seq = uint64(len(h.NodeList.Nodes) - 1)
+	}
+
 	return
 
 }
 
 // Private functions
 
+// Returns true if node 0 is the entry point placeholder created by New, i.e not counted as an insert. Indexes imported
+// from hnswlib have no placeholder
+func hasPlaceholder(seq uint64, nodes int) bool {
+	return seq < uint64(nodes)
+}
+
 // Find the min value - Use Go 1.21, inbuilt?
 func min(a, b int) int {
 	if a < b {
@@ -1003,3 +965,96 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+// Search, recording the path and level 0 expansions in `trace` if not nil
+func (h *HNSW) search(q *[]float32, results *queue.TopK, efSearch int, trace *Trace) (err error) {
+
+	err = h.checkDimension(*q)
+
+	if err != nil {
+		return err
+	}
+
+	// Traverse the graph using quantized vectors if enabled
+	dist := h.queryDistance(q)
+
+	var probe *searchProbe
+
+	if h.metrics != nil {
+		probe = newSearchProbe()
+		dist = probe.wrap(dist)
+	}
+
+	if trace != nil {
+		dist = trace.wrap(dist)
+	}
+
+	currentObj := &h.NodeList.Nodes[h.Ep]
+
+	if trace != nil {
+		trace.Ep = currentObj.Id
+		trace.MaxLevel = h.Maxlevel
+	}
+
+	match, currentDist, err := h.findEp(dist, currentObj, trace)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Visited list and heaps are reused across searches
+	ctx := getSearchContext(len(h.NodeList.Nodes))
+	defer putSearchContext(ctx)
+
+	top := &ctx.top
+	top.Reset()
+
+	if probe != nil {
+		probe.startLevel0()
+	}
+
+	if trace != nil {
+		trace.EpDistance = currentDist
+		trace.Visited = 1
+		ctx.trace = trace
+	}
+
+	err = h.searchLayer(ctx, dist, queue.Item{Node: match.Id, Distance: currentDist}, top, efSearch, 0)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Quantized distances are approximate, rerank the efSearch candidates using the exact distance
+	if h.Quantization != QuantizationNone {
+
+		err = h.rerank(q, top)
+
+		if err != nil {
+			return err
+		}
+
+		if trace != nil {
+			trace.Reranked = true
+		}
+
+	}
+
+	// Deleted nodes are traversed but not returned
+	h.NodeList.mutex.RLock()
+
+	for _, item := range top.Items() {
+		if !h.NodeList.Nodes[item.Node].Deleted {
+			results.Push(item)
+		}
+	}
+
+	h.NodeList.mutex.RUnlock()
+
+	if probe != nil {
+		probe.observe(h.metrics)
+	}
+
+	return nil
+
+}
@@ -1,7 +1,6 @@
 package hnsw_test
 
 import (
-	"container/heap"
 	"fmt"
 	"log"
 	"testing"
@@ -227,11 +226,8 @@ func Test_ValidateInsertSearch(t *testing.T) {
 
 				groundResults[i] = make([]uint32, tc.K)
 
-				for i2 := tc.K - 1; i2 >= 0; i2-- {
-					if bestCandidatesBrute.Len() > 0 {
-						item := heap.Pop(&bestCandidatesBrute).(*queue.Item)
-						groundResults[i][i2] = item.Node
-					}
+				for i2, item := range bestCandidatesBrute.Sorted() {
+					groundResults[i][i2] = item.Node
 				}
 
 			}
@@ -240,22 +236,19 @@ func Test_ValidateInsertSearch(t *testing.T) {
 			totalSearch := 0
 
 			for i := 0; i < len(vecs); i++ {
-				var bestCandidates queue.PriorityQueue
-				heap.Init(&bestCandidates)
-				err = h.Search(&vecs[i], &bestCandidates, tc.K, tc.Efconstruction)
+				bestCandidates := queue.NewTopK(tc.K)
+				err = h.Search(&vecs[i], bestCandidates, tc.Efconstruction)
 
 				if err != nil {
 					log.Fatal(err)
 				}
 
-				for i3 := tc.K - 1; i3 >= 0; i3-- {
+				if bestCandidates.Len() == 0 {
+					fmt.Println("No matches")
+				}
 
-					if bestCandidates.Len() == 0 {
-						fmt.Println("No matches")
-						break
-					}
+				for _, item := range bestCandidates.Sorted() {
 
-					item := heap.Pop(&bestCandidates).(*queue.Item)
 					totalSearch++
 
 					for k := tc.K - 1; k >= 0; k-- {
@@ -280,3 +273,36 @@ func Test_ValidateInsertSearch(t *testing.T) {
 	}
 
 }
+
+func Test_SearchDistinctResults(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(5000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	queries, err := vectors.GenerateRandomVectors(500, 16)
+	assert.Nil(t, err)
+
+	// The entry point of the level 0 search is never returned twice, also when a neighbour leads back to it
+	for i := range queries {
+
+		results := queue.NewTopK(10)
+		assert.Nil(t, h.Search(&queries[i], results, 10))
+
+		seen := map[uint32]bool{}
+
+		for _, item := range results.Sorted() {
+			assert.False(t, seen[item.Node], "node %d returned twice", item.Node)
+			seen[item.Node] = true
+		}
+
+	}
+
+}
@@ -0,0 +1,478 @@
+package hnsw
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// Header written by hnswlib HierarchicalNSW::saveIndex, fields are written back-to-back without padding
+type hnswlibHeader struct {
+	OffsetLevel0       uint64
+	MaxElements        uint64
+	CurElementCount    uint64
+	SizeDataPerElement uint64
+	LabelOffset        uint64
+	OffsetData         uint64
+	Maxlevel           int32
+	EnterpointNode     uint32
+	MaxM               uint64
+	MaxM0              uint64
+	M                  uint64
+	Mult               float64
+	EfConstruction     uint64
+}
+
+// hnswlib stores the link count in the first 2 bytes of each list, the 3rd byte holds the deleted flag
+const hnswlibCountMask = 0xffff
+const hnswlibDeletedByte = 2
+const hnswlibDeleteMark = 0x01
+
+// Size of the header in the file
+const hnswlibHeaderSize = 8*6 + 4 + 4 + 8*3 + 8 + 8
+
+// Import an index saved by hnswlib (Python `Index.save_index` or C++ `saveIndex`) using the l2 space with float32 vectors
+//
+// hnswlib labels are returned in node Id order, as HNSW uses the position of each node as its Id. Elements marked deleted
+// are imported as deleted nodes. hnswlib has no entry point placeholder, element i is node i.
+func ImportHnswlib(filename string) (h HNSW, labels []uint64, err error) {
+
+	file, err := os.Open(filename)
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return
+	}
+
+	reader := bufio.NewReader(file)
+
+	header := hnswlibHeader{}
+
+	err = binary.Read(reader, binary.LittleEndian, &header)
+
+	if err != nil {
+		return
+	}
+
+	// Sizes read from the file are checked against the bytes left before anything is allocated from them
+	remaining := uint64(info.Size()) - hnswlibHeaderSize
+
+	if header.CurElementCount == 0 {
+		err = errors.New("hnswlib index contains no elements")
+		return
+	}
+
+	if header.MaxM == 0 || header.MaxM0 == 0 || header.MaxM > hnswlibCountMask || header.MaxM0 > hnswlibCountMask {
+		err = fmt.Errorf("hnswlib index has invalid link list sizes (maxM %d, maxM0 %d)", header.MaxM, header.MaxM0)
+		return
+	}
+
+	sizeLinksLevel0 := header.MaxM0*4 + 4
+	sizeLinksPerElement := header.MaxM*4 + 4
+
+	if header.OffsetLevel0 != 0 || header.OffsetData != sizeLinksLevel0 || header.LabelOffset < header.OffsetData || header.SizeDataPerElement != header.LabelOffset+8 {
+		err = errors.New("hnswlib index layout not supported")
+		return
+	}
+
+	if header.SizeDataPerElement > remaining || header.CurElementCount > remaining/header.SizeDataPerElement {
+		err = fmt.Errorf("hnswlib index is truncated, %d elements of %d bytes exceed the file size", header.CurElementCount, header.SizeDataPerElement)
+		return
+	}
+
+	remaining -= header.CurElementCount * header.SizeDataPerElement
+
+	if uint64(header.EnterpointNode) >= header.CurElementCount {
+		err = fmt.Errorf("hnswlib entry point (%d) is not one of the %d elements", header.EnterpointNode, header.CurElementCount)
+		return
+	}
+
+	if header.Maxlevel < 0 {
+		err = fmt.Errorf("hnswlib index has an invalid max level (%d)", header.Maxlevel)
+		return
+	}
+
+	dataSize := header.LabelOffset - header.OffsetData
+
+	if dataSize%4 != 0 {
+		err = errors.New("hnswlib index must use float32 vectors")
+		return
+	}
+
+	dim := int(dataSize / 4)
+
+	h.M = int(header.M)
+	h.Mmax = int(header.MaxM)
+	h.Mmax0 = int(header.MaxM0)
+	h.Efconstruction = int(header.EfConstruction)
+	h.Ml = header.Mult
+	h.Ep = int64(header.EnterpointNode)
+	h.Maxlevel = int(header.Maxlevel)
+	h.TenantBruteForce = TenantBruteForce
+
+	// hnswlib always selects neighbours by heuristic
+	h.Heuristic = true
+
+	// Level 0 block, [count][maxM0 links][vector][label] per element
+	level0 := make([]byte, header.CurElementCount*header.SizeDataPerElement)
+
+	_, err = io.ReadFull(reader, level0)
+
+	if err != nil {
+		return
+	}
+
+	nodes := make([]Node, header.CurElementCount)
+	labels = make([]uint64, header.CurElementCount)
+
+	for i := range nodes {
+
+		element := level0[uint64(i)*header.SizeDataPerElement : uint64(i+1)*header.SizeDataPerElement]
+
+		node := &nodes[i]
+		node.Id = uint32(i)
+		node.Deleted = element[hnswlibDeletedByte]&hnswlibDeleteMark != 0
+
+		node.Vectors = make([]float32, dim)
+
+		for i2 := range node.Vectors {
+			node.Vectors[i2] = math.Float32frombits(binary.LittleEndian.Uint32(element[header.OffsetData+uint64(i2)*4:]))
+		}
+
+		labels[i] = binary.LittleEndian.Uint64(element[header.LabelOffset:])
+
+		// Connections for layer 0, upper layers are appended below once the level of the node is known
+		node.Connections = make([][]uint32, 1)
+
+		node.Connections[0], err = readHnswlibLinks(element[:sizeLinksLevel0], header.CurElementCount)
+
+		if err != nil {
+			return
+		}
+
+	}
+
+	// Upper layers, a list of [count][maxM links] per level above 0 for each element
+	for i := range nodes {
+
+		var linkListSize uint32
+
+		err = binary.Read(reader, binary.LittleEndian, &linkListSize)
+
+		if err != nil {
+			return
+		}
+
+		if remaining < 4 || uint64(linkListSize) > remaining-4 {
+			err = fmt.Errorf("hnswlib element %d link list size (%d) exceeds the file size", i, linkListSize)
+			return
+		}
+
+		remaining -= 4 + uint64(linkListSize)
+
+		if uint64(linkListSize)%sizeLinksPerElement != 0 {
+			err = fmt.Errorf("hnswlib element %d has an invalid link list size (%d)", i, linkListSize)
+			return
+		}
+
+		node := &nodes[i]
+		node.Layer = int(uint64(linkListSize) / sizeLinksPerElement)
+
+		if node.Layer > h.Maxlevel {
+			err = fmt.Errorf("hnswlib element %d is on level %d, above the max level (%d)", i, node.Layer, h.Maxlevel)
+			return
+		}
+
+		linkLists := make([]byte, linkListSize)
+
+		_, err = io.ReadFull(reader, linkLists)
+
+		if err != nil {
+			return
+		}
+
+		for level := 1; level <= node.Layer; level++ {
+
+			offset := uint64(level-1) * sizeLinksPerElement
+
+			var links []uint32
+
+			links, err = readHnswlibLinks(linkLists[offset:offset+sizeLinksPerElement], header.CurElementCount)
+
+			if err != nil {
+				return
+			}
+
+			node.Connections = append(node.Connections, links)
+
+		}
+
+	}
+
+	// Searches start on the max level from the entry point
+	if nodes[h.Ep].Layer != h.Maxlevel {
+		err = fmt.Errorf("hnswlib entry point (%d) is on level %d, not the max level (%d)", h.Ep, nodes[h.Ep].Layer, h.Maxlevel)
+		return
+	}
+
+	h.NodeList.setNodes(nodes, h.Mmax0)
+
+	// Every element is treated as an insert, there is no entry point placeholder
+	h.seq = uint64(len(h.NodeList.Nodes))
+
+	return
+
+}
+
+// Export the index in the hnswlib binary layout, loadable with hnswlib `Index.load_index` using the l2 space
+//
+// The entry point placeholder is not exported, labels are written for each other node in Id order, if nil the node Id is
+// used as the label. Deleted nodes are marked deleted.
+func (h *HNSW) ExportHnswlib(filename string, labels []uint64) (err error) {
+
+	// Export from a snapshot, so inserts can continue while the file is written
+	s := h.Snapshot()
+
+	if s.Meta.VectorsReleased {
+		return errors.New("hnswlib export requires the full precision vectors, released from the index")
+	}
+
+	// Node 0 is skipped if it is the placeholder
+	first := 0
+
+	if hasPlaceholder(s.Meta.Seq, len(s.Nodes)) {
+		first = 1
+	}
+
+	elements := s.Nodes[first:]
+
+	if len(elements) == 0 {
+		return errors.New("index contains no nodes")
+	}
+
+	if labels != nil && len(labels) != len(elements) {
+		return fmt.Errorf("expected %d labels, got %d", len(elements), len(labels))
+	}
+
+	// Element of each node, links to the placeholder are dropped
+	element := func(id uint32) (uint32, bool) {
+		return id - uint32(first), int(id) >= first
+	}
+
+	// The placeholder is the entry point until a node is inserted above level 0, the first node of the max level replaces it
+	ep, maxLevel := uint32(s.Meta.Ep), s.Meta.Maxlevel
+
+	if int(ep) < first {
+
+		ep, maxLevel = uint32(first), elements[0].Layer
+
+		for i := range elements {
+			if elements[i].Layer > maxLevel {
+				ep, maxLevel = uint32(first+i), elements[i].Layer
+			}
+		}
+
+	}
+
+	// hnswlib stores float32, float16 and bfloat16 vectors are converted
+	dim := uint64(len(nodeVector(s.Meta.VectorType, &elements[0])))
+
+	sizeLinksLevel0 := uint64(s.Meta.Mmax0)*4 + 4
+	sizeLinksPerElement := uint64(s.Meta.Mmax)*4 + 4
+
+	header := hnswlibHeader{
+		OffsetLevel0:       0,
+		MaxElements:        uint64(len(elements)),
+		CurElementCount:    uint64(len(elements)),
+		SizeDataPerElement: sizeLinksLevel0 + dim*4 + 8,
+		LabelOffset:        sizeLinksLevel0 + dim*4,
+		OffsetData:         sizeLinksLevel0,
+		Maxlevel:           int32(maxLevel),
+		EnterpointNode:     ep - uint32(first),
+		MaxM:               uint64(s.Meta.Mmax),
+		MaxM0:              uint64(s.Meta.Mmax0),
+		M:                  uint64(s.Meta.M),
+		Mult:               s.Meta.Ml,
+		EfConstruction:     uint64(s.Meta.Efconstruction),
+	}
+
+	file, err := os.Create(filename)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	err = binary.Write(writer, binary.LittleEndian, &header)
+
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, header.SizeDataPerElement)
+
+	for i := range elements {
+
+		node := &elements[i]
+		vector := nodeVector(s.Meta.VectorType, node)
+
+		if uint64(len(vector)) != dim {
+			return fmt.Errorf("node %d has %d dimensions, expected %d", node.Id, len(vector), dim)
+		}
+
+		var links []uint32
+
+		if len(node.Connections) > 0 {
+			links = exportHnswlibLinks(node.Connections[0], element)
+		}
+
+		err = putHnswlibLinks(buf[:sizeLinksLevel0], links, s.Meta.Mmax0)
+
+		if err != nil {
+			return fmt.Errorf("node %d, level 0: %w", node.Id, err)
+		}
+
+		if node.Deleted {
+			buf[hnswlibDeletedByte] |= hnswlibDeleteMark
+		}
+
+		for i2, v := range vector {
+			binary.LittleEndian.PutUint32(buf[header.OffsetData+uint64(i2)*4:], math.Float32bits(v))
+		}
+
+		label := uint64(node.Id)
+
+		if labels != nil {
+			label = labels[i]
+		}
+
+		binary.LittleEndian.PutUint64(buf[header.LabelOffset:], label)
+
+		_, err = writer.Write(buf)
+
+		if err != nil {
+			return err
+		}
+
+	}
+
+	linkList := make([]byte, sizeLinksPerElement)
+
+	for i := range elements {
+
+		node := &elements[i]
+
+		err = binary.Write(writer, binary.LittleEndian, uint32(uint64(node.Layer)*sizeLinksPerElement))
+
+		if err != nil {
+			return err
+		}
+
+		for level := 1; level <= node.Layer; level++ {
+
+			var links []uint32
+
+			if level < len(node.Connections) {
+				links = exportHnswlibLinks(node.Connections[level], element)
+			}
+
+			err = putHnswlibLinks(linkList, links, s.Meta.Mmax)
+
+			if err != nil {
+				return fmt.Errorf("node %d, level %d: %w", node.Id, level, err)
+			}
+
+			_, err = writer.Write(linkList)
+
+			if err != nil {
+				return err
+			}
+
+		}
+
+	}
+
+	err = writer.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+
+}
+
+// Private functions
+
+// Decode a [count][links] list, ignoring the deleted flag held in the upper bytes of count
+func readHnswlibLinks(buf []byte, numElements uint64) (links []uint32, err error) {
+
+	count := binary.LittleEndian.Uint32(buf) & hnswlibCountMask
+
+	if uint64(count) > uint64(len(buf)-4)/4 {
+		return nil, fmt.Errorf("hnswlib link count (%d) exceeds list capacity", count)
+	}
+
+	links = make([]uint32, count)
+
+	for i := range links {
+		links[i] = binary.LittleEndian.Uint32(buf[4+i*4:])
+
+		if uint64(links[i]) >= numElements {
+			return nil, fmt.Errorf("hnswlib link to element %d out of range", links[i])
+		}
+	}
+
+	return links, nil
+
+}
+
+// Element of each link, dropping those `element` does not export
+func exportHnswlibLinks(links []uint32, element func(id uint32) (uint32, bool)) []uint32 {
+
+	exported := make([]uint32, 0, len(links))
+
+	for _, link := range links {
+		if e, ok := element(link); ok {
+			exported = append(exported, e)
+		}
+	}
+
+	return exported
+
+}
+
+// Encode a [count][links] list, unused slots are zeroed as hnswlib does
+func putHnswlibLinks(buf []byte, links []uint32, maxLinks int) error {
+
+	if len(links) > maxLinks {
+		return fmt.Errorf("%d connections exceeds the maximum of %d", len(links), maxLinks)
+	}
+
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	binary.LittleEndian.PutUint32(buf, uint32(len(links)))
+
+	for i, link := range links {
+		binary.LittleEndian.PutUint32(buf[4+i*4:], link)
+	}
+
+	return nil
+
+}
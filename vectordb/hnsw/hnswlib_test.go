@@ -0,0 +1,318 @@
+package hnsw_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Saved by hnswlib, generated by testdata/hnswlib_fixture.py with what the tests expect in hnswlibExpected
+const hnswlibFixture = "testdata/hnswlib_l2.bin"
+const hnswlibExpected = "testdata/hnswlib_l2.json"
+
+// Laid out as hnswlib saveIndex writes it, generated by testdata/hnswlib_layout.py
+const hnswlibLayout = "testdata/hnswlib_layout.bin"
+
+type hnswlibFixtureData struct {
+	Dim            int         `json:"dim"`
+	M              int         `json:"m"`
+	EfConstruction int         `json:"ef_construction"`
+	Ef             int         `json:"ef"`
+	Labels         []uint64    `json:"labels"`
+	Vectors        [][]float32 `json:"vectors"`
+	Deleted        uint64      `json:"deleted"`
+	Queries        [][]float32 `json:"queries"`
+	Neighbours     [][]uint64  `json:"neighbours"`
+}
+
+func Test_ImportHnswlib(t *testing.T) {
+
+	buf, err := os.ReadFile(hnswlibExpected)
+
+	if os.IsNotExist(err) {
+		t.Skip("hnswlib fixture not generated, run testdata/hnswlib_fixture.py")
+	}
+
+	assert.Nil(t, err)
+
+	expected := hnswlibFixtureData{}
+	assert.Nil(t, json.Unmarshal(buf, &expected))
+
+	h, labels, err := hnsw.ImportHnswlib(hnswlibFixture)
+	assert.Nil(t, err)
+
+	assert.Equal(t, expected.M, h.M)
+	assert.Equal(t, expected.M, h.Mmax)
+	assert.Equal(t, expected.M*2, h.Mmax0)
+	assert.Equal(t, expected.EfConstruction, h.Efconstruction)
+	assert.Equal(t, expected.Labels, labels)
+	assert.Equal(t, uint64(len(labels)), h.Seq())
+	assert.Equal(t, h.Maxlevel, h.NodeList.Nodes[h.Ep].Layer)
+
+	for i := range labels {
+		assert.Equal(t, expected.Vectors[i], h.Vector(uint32(i)))
+		assert.Equal(t, labels[i] == expected.Deleted, h.NodeList.Nodes[i].Deleted)
+	}
+
+	assert.True(t, h.Validate().Valid())
+
+	// Same neighbours as hnswlib knn_query, which skips deleted elements as Search does
+	for i := range expected.Queries {
+
+		results := queue.NewTopK(len(expected.Neighbours[i]))
+		assert.Nil(t, h.Search(&expected.Queries[i], results, expected.Ef))
+
+		found := []uint64{}
+
+		for _, item := range results.Sorted() {
+			found = append(found, labels[item.Node])
+		}
+
+		assert.Equal(t, expected.Neighbours[i], found)
+
+	}
+
+	// No placeholder, every element is exported back
+	filename := filepath.Join(t.TempDir(), "export.bin")
+	assert.Nil(t, h.ExportHnswlib(filename, labels))
+
+	h2, labels2, err := hnsw.ImportHnswlib(filename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, labels, labels2)
+	assertSameHnswlibGraph(t, &h, &h2, 0)
+
+}
+
+func Test_ImportHnswlibLayout(t *testing.T) {
+
+	h, labels, err := hnsw.ImportHnswlib(hnswlibLayout)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, h.M)
+	assert.Equal(t, 2, h.Mmax)
+	assert.Equal(t, 4, h.Mmax0)
+	assert.Equal(t, 16, h.Efconstruction)
+	assert.Equal(t, int64(2), h.Ep)
+	assert.Equal(t, 2, h.Maxlevel)
+	assert.Equal(t, hnsw.TenantBruteForce, h.TenantBruteForce)
+
+	// Only the saved elements, not max_elements
+	assert.Equal(t, []uint64{10, 11, 12, 13, 14, 15}, labels)
+	assert.Equal(t, uint64(6), h.Seq())
+
+	vecs := [][]float32{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {2, 0}, {2, 2}}
+	layers := []int{1, 0, 2, 0, 1, 0}
+
+	for i := range vecs {
+		assert.Equal(t, vecs[i], h.Vector(uint32(i)))
+		assert.Equal(t, layers[i], h.NodeList.Nodes[i].Layer)
+		assert.Equal(t, i == 3, h.NodeList.Nodes[i].Deleted)
+	}
+
+	// The stale ids after each count are not links
+	connections := [][][]uint32{
+		{{1, 2, 3}, {2, 4}},
+		{{0, 3, 4}},
+		{{0, 3, 5}, {0, 4}, {}},
+		{{1, 2, 5, 4}},
+		{{1, 3, 5}, {0, 2}},
+		{{3, 4, 2}},
+	}
+
+	for i := range connections {
+
+		node := h.PeekNode(i)
+
+		for level := range connections[i] {
+			assert.ElementsMatch(t, connections[i][level], node.Connections[level])
+		}
+
+	}
+
+	assert.True(t, h.Validate().Valid())
+
+	// Element 3 is deleted, so the nearest of (1, 1) are its neighbours
+	results := queue.NewTopK(2)
+	assert.Nil(t, h.Search(&[]float32{1, 1}, results, 16))
+
+	found := []uint64{}
+
+	for _, item := range results.Sorted() {
+		found = append(found, labels[item.Node])
+	}
+
+	assert.ElementsMatch(t, []uint64{11, 12}, found)
+
+}
+
+func Test_ExportImportHnswlib(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(1000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := 0; i < len(vecs); i++ {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, h.Delete(10))
+
+	filename := filepath.Join(t.TempDir(), "export.bin")
+
+	err = h.ExportHnswlib(filename, nil)
+	assert.Nil(t, err)
+
+	h2, labels, err := hnsw.ImportHnswlib(filename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, h.M, h2.M)
+	assert.Equal(t, h.Mmax, h2.Mmax)
+	assert.Equal(t, h.Mmax0, h2.Mmax0)
+	assert.Equal(t, h.Efconstruction, h2.Efconstruction)
+	assert.Equal(t, h.Ml, h2.Ml)
+	assert.Equal(t, h.Ep-1, h2.Ep)
+	assert.Equal(t, h.Maxlevel, h2.Maxlevel)
+
+	// The placeholder is not exported, node i is element i-1 labelled i
+	assert.Equal(t, len(vecs), len(h2.NodeList.Nodes))
+	assert.Equal(t, uint64(len(vecs)), h2.Seq())
+
+	for i := range labels {
+		assert.Equal(t, uint64(i+1), labels[i])
+	}
+
+	assertSameHnswlibGraph(t, &h, &h2, 1)
+
+	assert.True(t, h2.NodeList.Nodes[9].Deleted)
+
+	// Node 0 is an element of an imported index
+	assert.Nil(t, h2.Delete(0))
+
+	_, _, err = hnsw.ImportHnswlib(filepath.Join(t.TempDir(), "missing.bin"))
+	assert.NotNil(t, err)
+
+}
+
+func Test_ExportHnswlibPlaceholderEp(t *testing.T) {
+
+	// Every node on level 0, the placeholder is still the entry point
+	h, err := hnsw.New(8, 8, 16, 100, 2)
+	assert.Nil(t, err)
+
+	h.Ml = 0
+
+	for i := 0; i < 10; i++ {
+		_, err := h.Insert([]float32{float32(i), 1})
+		assert.Nil(t, err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "export.bin")
+	assert.Nil(t, h.ExportHnswlib(filename, nil))
+
+	h2, _, err := hnsw.ImportHnswlib(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), h2.Ep)
+
+}
+
+func Test_ImportHnswlibInvalid(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(100, 4)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 50, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "export.bin")
+	assert.Nil(t, h.ExportHnswlib(filename, nil))
+
+	valid, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+
+	// Header field offsets
+	const curElementCount, maxlevel, enterpointNode, maxM0 = 16, 48, 52, 64
+
+	corrupt := map[string]func(buf []byte) []byte{
+		"entry point": func(buf []byte) []byte {
+			binary.LittleEndian.PutUint32(buf[enterpointNode:], 100)
+			return buf
+		},
+		"max level": func(buf []byte) []byte {
+			binary.LittleEndian.PutUint32(buf[maxlevel:], uint32(h.Maxlevel+1))
+			return buf
+		},
+		"negative max level": func(buf []byte) []byte {
+			binary.LittleEndian.PutUint32(buf[maxlevel:], 0xffffffff)
+			return buf
+		},
+		"element count": func(buf []byte) []byte {
+			binary.LittleEndian.PutUint64(buf[curElementCount:], 1<<60)
+			return buf
+		},
+		"links": func(buf []byte) []byte {
+			binary.LittleEndian.PutUint64(buf[maxM0:], 1<<62)
+			return buf
+		},
+		"truncated": func(buf []byte) []byte {
+			return buf[:len(buf)-10]
+		},
+	}
+
+	for name, f := range corrupt {
+
+		buf := f(append([]byte{}, valid...))
+		assert.Nil(t, os.WriteFile(filename, buf, 0644))
+
+		_, _, err := hnsw.ImportHnswlib(filename)
+		assert.NotNil(t, err, name)
+
+	}
+
+}
+
+// Node i of `h` is node i-offset of `h2`
+func assertSameHnswlibGraph(t *testing.T, h *hnsw.HNSW, h2 *hnsw.HNSW, offset int) {
+
+	for i := offset; i < len(h.NodeList.Nodes); i++ {
+
+		node := h.PeekNode(i)
+		node2 := h2.PeekNode(i - offset)
+
+		assert.Equal(t, node.Layer, node2.Layer)
+		assert.Equal(t, node.Deleted, node2.Deleted)
+		assert.Equal(t, h.Vector(uint32(i)), h2.Vector(uint32(i-offset)))
+
+		for level := 0; level <= node.Layer; level++ {
+
+			links := []uint32{}
+
+			for _, link := range node.Connections[level] {
+				if int(link) >= offset {
+					links = append(links, link-uint32(offset))
+				}
+			}
+
+			assert.ElementsMatch(t, links, node2.Connections[level])
+
+		}
+
+	}
+
+}
@@ -0,0 +1,86 @@
+package hnsw
+
+import "time"
+
+// Receives measurements of inserts and searches, see WithMetrics. Implementations must be safe for concurrent use. With
+// no metrics set the index only checks for nil, the distances are not counted and the clock is not read.
+type Metrics interface {
+	ObserveInsert(latency time.Duration)
+
+	// Distances computed to traverse the graph (quantized if enabled, excluding rerank) and nodes visited on level 0
+	ObserveSearch(latency time.Duration, distances int, visited int)
+}
+
+// Report inserts and searches to `m`
+func WithMetrics(m Metrics) Option {
+
+	return func(h *HNSW) error {
+		h.metrics = m
+		return nil
+	}
+
+}
+
+// Report inserts and searches to `m`, e.g for an index returned by Load. Must be set before the index is in use, nil to
+// disable
+func (h *HNSW) SetMetrics(m Metrics) {
+	h.metrics = m
+}
+
+// Current top layer of the graph
+func (h *HNSW) MaxLevel() int {
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.Maxlevel
+
+}
+
+// Number of nodes in the graph, including deleted nodes and the entry point placeholder
+func (h *HNSW) Len() int {
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	return len(h.NodeList.Nodes)
+
+}
+
+// Private functions
+
+// Measurements of a single search, only used when metrics are enabled
+type searchProbe struct {
+	start     time.Time
+	distances int
+	level0    int // Distances computed before the level 0 search
+}
+
+func newSearchProbe() *searchProbe {
+	return &searchProbe{start: time.Now()}
+}
+
+// Count the calls to `dist`
+func (p *searchProbe) wrap(dist queryDistance) queryDistance {
+
+	return func(id uint32) float32 {
+		p.distances++
+		return dist(id)
+	}
+
+}
+
+// Mark the start of the level 0 search, nodes visited are counted from here
+func (p *searchProbe) startLevel0() {
+	p.level0 = p.distances
+}
+
+// Report the search, the entry point counts as visited on level 0
+func (p *searchProbe) observe(m Metrics) {
+	m.ObserveSearch(time.Since(p.start), p.distances, p.distances-p.level0+1)
+}
+
+// Report a scan of `n` nodes, each visited with one distance computed
+func (p *searchProbe) observeScan(m Metrics, n int) {
+	m.ObserveSearch(time.Since(p.start), n, n)
+}
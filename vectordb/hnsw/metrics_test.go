@@ -0,0 +1,93 @@
+package hnsw_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Records each observation
+type recordedMetrics struct {
+	mutex     sync.Mutex
+	inserts   int
+	searches  int
+	distances []int
+	visited   []int
+}
+
+func (m *recordedMetrics) ObserveInsert(latency time.Duration) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.inserts++
+
+}
+
+func (m *recordedMetrics) ObserveSearch(latency time.Duration, distances int, visited int) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.searches++
+	m.distances = append(m.distances, distances)
+	m.visited = append(m.visited, visited)
+
+}
+
+func Test_Metrics(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(1000, 16)
+	assert.Nil(t, err)
+
+	m := &recordedMetrics{}
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]), hnsw.WithMetrics(m))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+
+		tenant := uint32(1 + i%2)
+
+		// Tenant 2 is scanned
+		if i%10 != 1 {
+			tenant = 1
+		}
+
+		_, err := h.InsertTenant(vecs[i], tenant)
+		assert.Nil(t, err)
+
+	}
+
+	assert.Equal(t, 1000, m.inserts)
+	assert.Equal(t, 100, h.TenantLen(2))
+
+	assert.Nil(t, h.Search(&vecs[0], queue.NewTopK(10), 50))
+	assert.Nil(t, h.SearchTenant(&vecs[0], queue.NewTopK(10), 50, 1))
+	assert.Nil(t, h.SearchTenant(&vecs[0], queue.NewTopK(10), 50, 2))
+
+	assert.Equal(t, 3, m.searches)
+
+	// The upper layers add distances but no visits on level 0
+	for i := 0; i < 2; i++ {
+		assert.GreaterOrEqual(t, m.visited[i], 50)
+		assert.GreaterOrEqual(t, m.distances[i], m.visited[i])
+	}
+
+	assert.Equal(t, 100, m.distances[2])
+	assert.Equal(t, 100, m.visited[2])
+
+	assert.GreaterOrEqual(t, h.MaxLevel(), 1)
+
+	// Disabled
+	h.SetMetrics(nil)
+
+	assert.Nil(t, h.Search(&vecs[0], queue.NewTopK(10), 50))
+	assert.Equal(t, 3, m.searches)
+
+}
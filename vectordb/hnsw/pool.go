@@ -0,0 +1,98 @@
+package hnsw
+
+import (
+	"sync"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+)
+
+// Visited set for a search, each node is tagged with the epoch it was last visited in so the list never needs clearing
+// between searches (cleared only when the epoch wraps)
+type visitedList struct {
+	tags  []uint16
+	epoch uint16
+}
+
+// Scratch space reused across searches, a context is held by a single search or insert at a time
+type searchContext struct {
+	visited    visitedList
+	candidates queue.MinHeap
+	top        queue.MaxHeap // Results of Search or the candidates of an insert, before they are copied out
+	trace      *Trace        // Expansions are recorded by searchLayer if set, see SearchTrace
+}
+
+var searchContextPool = sync.Pool{
+	New: func() any {
+		return &searchContext{}
+	},
+}
+
+// Get a context from the pool, for an index of `size` nodes
+func getSearchContext(size int) *searchContext {
+
+	ctx := searchContextPool.Get().(*searchContext)
+	ctx.visited.grow(size)
+
+	return ctx
+
+}
+
+// Return the context to the pool
+func putSearchContext(ctx *searchContext) {
+
+	ctx.candidates.Reset()
+	ctx.top.Reset()
+	ctx.trace = nil
+
+	searchContextPool.Put(ctx)
+
+}
+
+// Start a new epoch, every node is unvisited
+func (v *visitedList) reset() {
+
+	v.epoch++
+
+	if v.epoch == 0 {
+
+		for i := range v.tags {
+			v.tags[i] = 0
+		}
+
+		v.epoch = 1
+
+	}
+
+}
+
+// Grow the list to at least `size` nodes
+func (v *visitedList) grow(size int) {
+
+	if size <= len(v.tags) {
+		return
+	}
+
+	// Headroom for nodes inserted while the context is in use
+	tags := make([]uint16, size+size/4)
+	copy(tags, v.tags)
+
+	v.tags = tags
+
+}
+
+// Mark node `id` as visited, returns true if it was already visited in the current epoch
+func (v *visitedList) visit(id uint32) bool {
+
+	if int(id) >= len(v.tags) {
+		v.grow(int(id) + 1)
+	}
+
+	if v.tags[id] == v.epoch {
+		return true
+	}
+
+	v.tags[id] = v.epoch
+
+	return false
+
+}
@@ -0,0 +1,103 @@
+package hnsw_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSearchIndex(tb testing.TB, num int, dim int) (h hnsw.HNSW, vecs [][]float32) {
+
+	vecs, err := vectors.GenerateRandomVectors(num, dim)
+	assert.Nil(tb, err)
+
+	h, err = hnsw.New(16, 16, 32, 100, dim)
+	assert.Nil(tb, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(tb, err)
+	}
+
+	return
+
+}
+
+// Search results are copied out of the pooled context, later searches must not modify them
+func Test_SearchResultsOwned(t *testing.T) {
+
+	h, vecs := newSearchIndex(t, 1000, 16)
+
+	first := queue.NewTopK(10)
+
+	err := h.Search(&vecs[0], first, 50)
+	assert.Nil(t, err)
+
+	expected := first.Sorted()
+
+	for i := 1; i < 100; i++ {
+		err := h.Search(&vecs[i], queue.NewTopK(10), 50)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, expected, first.Sorted())
+
+	// Nearest first, and the query is found
+	assert.Equal(t, 10, len(expected))
+	assert.Equal(t, uint32(1), expected[0].Node)
+
+	for i := 1; i < len(expected); i++ {
+		assert.LessOrEqual(t, expected[i-1].Distance, expected[i].Distance)
+	}
+
+}
+
+// Results already collected are kept if nearer, e.g merging the results of two searches
+func Test_SearchMergeResults(t *testing.T) {
+
+	h, vecs := newSearchIndex(t, 1000, 16)
+
+	results := queue.NewTopK(10)
+
+	err := h.Search(&vecs[0], results, 50)
+	assert.Nil(t, err)
+
+	expected := results.Sorted()
+
+	// Result of another index, between the 5th and 6th nearest
+	other := queue.Item{Node: 5000, Distance: (expected[4].Distance + expected[5].Distance) / 2}
+
+	results = queue.NewTopK(10)
+	results.Push(other)
+
+	err = h.Search(&vecs[0], results, 50)
+	assert.Nil(t, err)
+
+	merged := results.Sorted()
+
+	assert.Equal(t, 10, len(merged))
+	assert.Equal(t, expected[:5], merged[:5])
+	assert.Equal(t, other, merged[5])
+	assert.Equal(t, expected[5:9], merged[6:])
+
+}
+
+func Benchmark_Search(b *testing.B) {
+
+	h, vecs := newSearchIndex(b, 10000, 32)
+
+	bestCandidates := queue.NewTopK(10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bestCandidates.Reset()
+
+		h.Search(&vecs[i%len(vecs)], bestCandidates, 100)
+	}
+
+}
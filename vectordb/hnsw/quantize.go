@@ -0,0 +1,314 @@
+package hnsw
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/quantization"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+)
+
+// Vector quantization used during search, the full precision vectors are kept for inserts and to rerank results
+type Quantization int
+
+const (
+	QuantizationNone   Quantization = iota
+	QuantizationInt8                // Scalar quantization, 1 byte per dimension
+	QuantizationPQ                  // Product quantization, 1 byte per subspace
+	QuantizationBinary              // Binary quantization, 1 bit per dimension compared by Hamming distance
+)
+
+func (q Quantization) String() string {
+
+	switch q {
+	case QuantizationInt8:
+		return "int8"
+	case QuantizationPQ:
+		return "pq"
+	case QuantizationBinary:
+		return "binary"
+	}
+
+	return "none"
+
+}
+
+// Distance used to rerank the candidates of a quantized search, the graph is always built using L2
+type Metric int
+
+const (
+	MetricL2     Metric = iota // Squared L2
+	MetricCosine               // 1 - cosine similarity, the same order as L2 for normalised vectors
+)
+
+func (m Metric) String() string {
+
+	switch m {
+	case MetricCosine:
+		return "cosine"
+	}
+
+	return "l2"
+
+}
+
+// Options applied by New
+type Option func(h *HNSW) error
+
+// Distance from the query to node `id`
+type queryDistance func(id uint32) float32
+
+// Full precision vectors kept outside of the index (e.g on disk), by node id. Used to rerank search results once the
+// in-memory vectors are released, see ReleaseVectors
+type VectorStore interface {
+	Vector(id uint32) ([]float32, error)
+}
+
+// Traverse the graph using int8 scalar quantized vectors, the quantizer is trained from `sample`
+func WithScalarQuantizer(sample [][]float32) Option {
+
+	return func(h *HNSW) (err error) {
+
+		if len(sample) == 0 {
+			return errors.New("scalar quantizer requires a training sample")
+		}
+
+		err = h.checkSample(sample)
+
+		if err != nil {
+			return err
+		}
+
+		h.ScalarQuantizer, err = quantization.TrainScalarQuantizer(sample)
+
+		if err != nil {
+			return err
+		}
+
+		h.Quantization = QuantizationInt8
+
+		return nil
+
+	}
+
+}
+
+// Traverse the graph using product quantized codes (`m` bytes per vector), the codebooks are trained from `sample`
+func WithProductQuantizer(sample [][]float32, m int) Option {
+
+	return func(h *HNSW) (err error) {
+
+		if len(sample) == 0 {
+			return errors.New("product quantizer requires a training sample")
+		}
+
+		err = h.checkSample(sample)
+
+		if err != nil {
+			return err
+		}
+
+		h.ProductQuantizer, err = quantization.TrainProductQuantizer(sample, m)
+
+		if err != nil {
+			return err
+		}
+
+		h.Quantization = QuantizationPQ
+
+		return nil
+
+	}
+
+}
+
+// Traverse the graph using 1-bit codes compared by Hamming distance, and rerank the efSearch candidates using the
+// `rerank` metric on the full precision vectors. The threshold of each bit is the mean of the dimension in `sample`.
+func WithBinaryQuantizer(sample [][]float32, rerank Metric) Option {
+
+	return func(h *HNSW) (err error) {
+
+		if len(sample) == 0 {
+			return errors.New("binary quantizer requires a training sample")
+		}
+
+		err = h.checkSample(sample)
+
+		if err != nil {
+			return err
+		}
+
+		h.BinaryQuantizer, err = quantization.TrainBinaryQuantizer(sample)
+
+		if err != nil {
+			return err
+		}
+
+		h.Quantization = QuantizationBinary
+		h.RerankMetric = rerank
+
+		return nil
+
+	}
+
+}
+
+// Release the in-memory full precision vectors once the index is built, searches then traverse the graph using the
+// quantized codes only and rerank from `store`, or return the approximate distances if store is nil. Inserts and
+// BruteSearch are no longer possible. Call again after Load to set the store.
+func (h *HNSW) ReleaseVectors(store VectorStore) error {
+
+	if h.Quantization == QuantizationNone {
+		return errors.New("vectors can only be released from a quantized index")
+	}
+
+	h.writeGate.Lock()
+	defer h.writeGate.Unlock()
+
+	h.NodeList.mutex.Lock()
+	defer h.NodeList.mutex.Unlock()
+
+	h.NodeList.vectors = nil
+	h.NodeList.half = nil
+
+	h.VectorsReleased = true
+	h.vectorStore = store
+
+	return nil
+
+}
+
+// Bytes used to store the vectors (float32, float16 or bfloat16) and the quantized codes of all nodes
+func (h *HNSW) VectorMemory() (vectorBytes int64, codeBytes int64) {
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	vectorBytes = int64(len(h.NodeList.vectors))*4 + int64(len(h.NodeList.half))*2
+
+	for i := range h.NodeList.Nodes {
+		codeBytes += int64(len(h.NodeList.Nodes[i].Codes))
+	}
+
+	return
+
+}
+
+// Private functions
+
+// Return an error if a vector of the training `sample` does not have the index dimension
+func (h *HNSW) checkSample(sample [][]float32) error {
+
+	for i := range sample {
+		if len(sample[i]) != h.NodeList.dim {
+			return fmt.Errorf("sample vector %d has %d dimensions, the index has %d", i, len(sample[i]), h.NodeList.dim)
+		}
+	}
+
+	return nil
+
+}
+
+// Quantize a vector for storage, nil if quantization is disabled
+func (h *HNSW) encode(v []float32) []uint8 {
+
+	switch h.Quantization {
+	case QuantizationInt8:
+		return h.ScalarQuantizer.Encode(v)
+	case QuantizationPQ:
+		return h.ProductQuantizer.Encode(v)
+	case QuantizationBinary:
+		return h.BinaryQuantizer.Encode(v)
+	}
+
+	return nil
+
+}
+
+// Distance used to traverse the graph during search, using the quantized codes if enabled
+func (h *HNSW) queryDistance(q *[]float32) queryDistance {
+
+	switch h.Quantization {
+
+	case QuantizationInt8:
+		sq := h.ScalarQuantizer
+
+		return func(id uint32) float32 {
+			return sq.Distance(*q, h.NodeList.Nodes[id].Codes)
+		}
+
+	case QuantizationPQ:
+		pq := h.ProductQuantizer
+
+		// Asymmetric distance computation, the lookup table is built once per query
+		table := pq.DistanceTable(*q)
+
+		return func(id uint32) float32 {
+			return pq.TableDistance(table, h.NodeList.Nodes[id].Codes)
+		}
+
+	case QuantizationBinary:
+		bq := h.BinaryQuantizer
+		code := bq.Encode(*q)
+
+		return func(id uint32) float32 {
+			return float32(bq.Distance(code, h.NodeList.Nodes[id].Codes))
+		}
+
+	}
+
+	return h.exactDistance(q)
+
+}
+
+// Exact distance using the full precision vectors
+func (h *HNSW) exactDistance(q *[]float32) queryDistance {
+
+	return func(id uint32) float32 {
+		return h.distanceTo(q, id)
+	}
+
+}
+
+// Replace the approximate distances of the candidates (max-heap) with the exact distance using the rerank metric,
+// from the vector store if the in-memory vectors are released
+func (h *HNSW) rerank(q *[]float32, topCandidates *queue.MaxHeap) error {
+
+	if h.VectorsReleased && h.vectorStore == nil {
+		return nil
+	}
+
+	items := topCandidates.Items()
+
+	for i := range items {
+
+		item := &items[i]
+		v := h.Vector(item.Node)
+
+		if h.VectorsReleased {
+
+			var err error
+
+			v, err = h.vectorStore.Vector(item.Node)
+
+			if err != nil {
+				return err
+			}
+
+		}
+
+		switch h.RerankMetric {
+		case MetricCosine:
+			item.Distance, _ = distance.Cosine(q, &v)
+		default:
+			item.Distance, _ = distance.L2_Opt(q, &v)
+		}
+
+	}
+
+	topCandidates.Init()
+
+	return nil
+
+}
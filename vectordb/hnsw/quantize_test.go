@@ -0,0 +1,294 @@
+package hnsw_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Recall of Search against BruteSearch for each vector, with K results
+func searchRecall(t *testing.T, h *hnsw.HNSW, vecs [][]float32, K int, efSearch int) float64 {
+
+	hits := 0
+
+	for i := range vecs {
+
+		truth, err := h.BruteSearch(&vecs[i], K)
+		assert.Nil(t, err)
+
+		expected := make(map[uint32]bool)
+
+		for _, item := range truth.Sorted() {
+			expected[item.Node] = true
+		}
+
+		bestCandidates := queue.NewTopK(K)
+
+		err = h.Search(&vecs[i], bestCandidates, efSearch)
+		assert.Nil(t, err)
+
+		for _, item := range bestCandidates.Sorted() {
+			if expected[item.Node] {
+				hits++
+			}
+		}
+
+	}
+
+	return float64(hits) / float64(len(vecs)*K)
+
+}
+
+func Test_ScalarQuantization(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(2000, 32)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]), hnsw.WithScalarQuantizer(vecs[:500]))
+	assert.Nil(t, err)
+
+	assert.Equal(t, hnsw.QuantizationInt8, h.Quantization)
+	assert.Equal(t, "int8", h.Quantization.String())
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, 32, len(h.NodeList.Nodes[1].Codes))
+
+	vectorBytes, codeBytes := h.VectorMemory()
+	assert.Equal(t, int64(len(h.NodeList.Nodes)*32*4), vectorBytes)
+	assert.Equal(t, int64(len(h.NodeList.Nodes)*32), codeBytes)
+
+	assert.GreaterOrEqual(t, searchRecall(t, &h, vecs[:200], 10, 100), 0.95)
+
+	// Results are reranked, distances are exact
+	bestCandidates := queue.NewTopK(10)
+
+	err = h.Search(&vecs[5], bestCandidates, 100)
+	assert.Nil(t, err)
+
+	for _, item := range bestCandidates.Sorted() {
+		v := h.Vector(item.Node)
+		exact, _ := distance.L2_Opt(&vecs[5], &v)
+		assert.Equal(t, exact, item.Distance)
+	}
+
+	// Quantizer and codes are persisted
+	filename := filepath.Join(t.TempDir(), "vector.gob")
+
+	err = h.Save(filename)
+	assert.Nil(t, err)
+
+	h2, err := hnsw.Load(filename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, hnsw.QuantizationInt8, h2.Quantization)
+	assert.Equal(t, h.ScalarQuantizer, h2.ScalarQuantizer)
+	assert.Equal(t, h.NodeList.Nodes[10].Codes, h2.NodeList.Nodes[10].Codes)
+
+	assert.GreaterOrEqual(t, searchRecall(t, &h2, vecs[:200], 10, 100), 0.95)
+
+}
+
+func Test_ScalarQuantizationNoSample(t *testing.T) {
+
+	_, err := hnsw.New(16, 16, 32, 100, 8, hnsw.WithScalarQuantizer(nil))
+	assert.NotNil(t, err)
+
+}
+
+func Test_ScalarQuantizationSampleDimension(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(100, 8)
+	assert.Nil(t, err)
+
+	// Sample of another dimension than the index
+	_, err = hnsw.New(16, 16, 32, 100, 16, hnsw.WithScalarQuantizer(vecs))
+	assert.NotNil(t, err)
+
+	// Sample vectors of different dimensions
+	_, err = hnsw.New(16, 16, 32, 100, 8, hnsw.WithScalarQuantizer(append(vecs, make([]float32, 4))))
+	assert.NotNil(t, err)
+
+}
+
+// Full precision vectors by node id, node 0 is the entry-point created by New
+type sliceStore [][]float32
+
+func (s sliceStore) Vector(id uint32) ([]float32, error) {
+	return s[id], nil
+}
+
+func Test_ProductQuantization(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(2000, 32)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]), hnsw.WithProductQuantizer(vecs[:1000], 16))
+	assert.Nil(t, err)
+
+	assert.Equal(t, hnsw.QuantizationPQ, h.Quantization)
+	assert.Equal(t, "pq", h.Quantization.String())
+
+	store := sliceStore{make([]float32, 32)}
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+		store = append(store, vecs[i])
+	}
+
+	assert.Equal(t, 16, len(h.NodeList.Nodes[1].Codes))
+
+	_, codeBytes := h.VectorMemory()
+	assert.Equal(t, int64(len(h.NodeList.Nodes)*16), codeBytes)
+
+	recall := searchRecall(t, &h, vecs[:200], 10, 100)
+	assert.GreaterOrEqual(t, recall, 0.9)
+
+	// Codebooks are persisted
+	filename := filepath.Join(t.TempDir(), "vector.gob")
+
+	err = h.Save(filename)
+	assert.Nil(t, err)
+
+	// Rerank from the store once the in-memory vectors are released
+	err = h.ReleaseVectors(store)
+	assert.Nil(t, err)
+
+	vectorBytes, _ := h.VectorMemory()
+	assert.Equal(t, int64(0), vectorBytes)
+
+	_, err = h.Insert(vecs[0])
+	assert.NotNil(t, err)
+
+	_, err = h.BruteSearch(&vecs[0], 10)
+	assert.NotNil(t, err)
+
+	bestCandidates := queue.NewTopK(10)
+
+	err = h.Search(&vecs[5], bestCandidates, 100)
+	assert.Nil(t, err)
+
+	for _, item := range bestCandidates.Sorted() {
+		exact, _ := distance.L2_Opt(&vecs[5], &store[item.Node])
+		assert.Equal(t, exact, item.Distance)
+	}
+
+	h2, err := hnsw.Load(filename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, hnsw.QuantizationPQ, h2.Quantization)
+	assert.Equal(t, h.ProductQuantizer, h2.ProductQuantizer)
+	assert.Equal(t, h.NodeList.Nodes[10].Codes, h2.NodeList.Nodes[10].Codes)
+
+	// Without a store the approximate (ADC) distances are returned
+	err = h2.ReleaseVectors(nil)
+	assert.Nil(t, err)
+
+	bestCandidates = queue.NewTopK(10)
+
+	err = h2.Search(&vecs[5], bestCandidates, 100)
+	assert.Nil(t, err)
+
+	table := h2.ProductQuantizer.DistanceTable(vecs[5])
+
+	for _, item := range bestCandidates.Sorted() {
+		assert.Equal(t, h2.ProductQuantizer.TableDistance(table, h2.NodeList.Nodes[item.Node].Codes), item.Distance)
+	}
+
+}
+
+func Test_ProductQuantizationSampleDimension(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(300, 8)
+	assert.Nil(t, err)
+
+	_, err = hnsw.New(16, 16, 32, 100, 16, hnsw.WithProductQuantizer(vecs, 4))
+	assert.NotNil(t, err)
+
+	_, err = hnsw.New(16, 16, 32, 100, 8, hnsw.WithProductQuantizer(append(vecs, make([]float32, 4)), 4))
+	assert.NotNil(t, err)
+
+}
+
+func Test_ReleaseVectorsUnquantized(t *testing.T) {
+
+	h, err := hnsw.New(16, 16, 32, 100, 8)
+	assert.Nil(t, err)
+
+	err = h.ReleaseVectors(nil)
+	assert.NotNil(t, err)
+
+}
+
+func Test_BinaryQuantization(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(2000, 128)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]), hnsw.WithBinaryQuantizer(vecs[:500], hnsw.MetricL2))
+	assert.Nil(t, err)
+
+	assert.Equal(t, hnsw.QuantizationBinary, h.Quantization)
+	assert.Equal(t, "binary", h.Quantization.String())
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	// 1 bit per dimension
+	assert.Equal(t, 16, len(h.NodeList.Nodes[1].Codes))
+
+	// Hamming distance is coarse, a wider efSearch is reranked
+	assert.GreaterOrEqual(t, searchRecall(t, &h, vecs[:200], 10, 200), 0.75)
+
+	// Rerank with cosine distance
+	h.RerankMetric = hnsw.MetricCosine
+
+	bestCandidates := queue.NewTopK(10)
+
+	err = h.Search(&vecs[5], bestCandidates, 100)
+	assert.Nil(t, err)
+
+	for _, item := range bestCandidates.Sorted() {
+		v := h.Vector(item.Node)
+		exact, _ := distance.Cosine(&vecs[5], &v)
+		assert.Equal(t, exact, item.Distance)
+	}
+
+	// Thresholds and the rerank metric are persisted
+	filename := filepath.Join(t.TempDir(), "vector.gob")
+
+	err = h.Save(filename)
+	assert.Nil(t, err)
+
+	h2, err := hnsw.Load(filename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, hnsw.QuantizationBinary, h2.Quantization)
+	assert.Equal(t, hnsw.MetricCosine, h2.RerankMetric)
+	assert.Equal(t, h.BinaryQuantizer, h2.BinaryQuantizer)
+
+}
+
+func Test_BinaryQuantizationSampleDimension(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(100, 8)
+	assert.Nil(t, err)
+
+	_, err = hnsw.New(16, 16, 32, 100, 16, hnsw.WithBinaryQuantizer(vecs, hnsw.MetricL2))
+	assert.NotNil(t, err)
+
+	_, err = hnsw.New(16, 16, 32, 100, 8, hnsw.WithBinaryQuantizer(append(vecs, make([]float32, 4)), hnsw.MetricL2))
+	assert.NotNil(t, err)
+
+}
@@ -0,0 +1,116 @@
+package hnsw
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+)
+
+// A Snapshot is a point-in-time copy of the HNSW graph, taken while inserts may still be running.
+type Snapshot struct {
+	Meta  HNSW_Meta
+	Nodes []Node
+
+	// Inserts are assigned sequence numbers in node Id order, the snapshot contains every insert with Seq <= Meta.Seq,
+	// a WAL replaying from Meta.Seq+1 restores the index to the current state
+	Seq uint64
+}
+
+// Take a consistent snapshot of the index
+//
+// Writers are paused only while the node list is copied, in-flight inserts are allowed to complete first. Vectors are
+// never modified once inserted so are shared with the live index arena. Level 0 links are modified in place so are
+// copied, upper level connection lists are copy-on-write (AddConnections either appends beyond the length captured
+// here, or replaces the list), so only the slice headers are copied.
+func (h *HNSW) Snapshot() (s Snapshot) {
+
+	// Wait for in-flight inserts, new inserts block until the copy is complete
+	h.writeGate.Lock()
+	defer h.writeGate.Unlock()
+
+	h.mutex.RLock()
+	s.Meta = HNSW_Meta{
+		Efconstruction: h.Efconstruction,
+		M:              h.M,
+		Mmax:           h.Mmax,
+		Mmax0:          h.Mmax0,
+		Ml:             h.Ml,
+		Ep:             h.Ep,
+		Maxlevel:       h.Maxlevel,
+		Heuristic:      h.Heuristic,
+		Seq:            h.seq,
+
+		Quantization:     h.Quantization,
+		ScalarQuantizer:  h.ScalarQuantizer,
+		ProductQuantizer: h.ProductQuantizer,
+		BinaryQuantizer:  h.BinaryQuantizer,
+		RerankMetric:     h.RerankMetric,
+		VectorsReleased:  h.VectorsReleased,
+		VectorType:       h.VectorType,
+	}
+	h.mutex.RUnlock()
+
+	s.Seq = s.Meta.Seq
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	s.Nodes = make([]Node, len(h.NodeList.Nodes))
+
+	for i := range h.NodeList.Nodes {
+		s.Nodes[i] = h.NodeList.node(uint32(i))
+	}
+
+	return s
+
+}
+
+// Write the snapshot to disk, in the same format as Save (loaded with Load)
+func (s *Snapshot) Save(filename string) (err error) {
+
+	// Dump meta-data
+	file, err := os.Create(fmt.Sprintf("%s.meta", filename))
+
+	if err != nil {
+		return err
+	}
+
+	encoder := gob.NewEncoder(file)
+
+	err = encoder.Encode(s.Meta)
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	file.Close()
+
+	file, err = os.Create(filename)
+
+	if err != nil {
+		return err
+	}
+
+	encoder = gob.NewEncoder(file)
+
+	err = encoder.Encode(s.Nodes)
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+
+}
+
+// Sequence number of the last insert applied to the index
+func (h *HNSW) Seq() uint64 {
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	return h.seq
+
+}
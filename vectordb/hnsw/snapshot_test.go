@@ -0,0 +1,96 @@
+package hnsw_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SnapshotConcurrentInsert(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(4000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	resultChan, jobs, err := h.InsertConcurrent(len(vecs))
+	assert.Nil(t, err)
+
+	snapshots := make([]hnsw.Snapshot, 0)
+
+	for i := 0; i < len(vecs); i++ {
+		jobs <- vecs[i]
+
+		// Snapshot while the workers are still inserting
+		if i > 0 && i%1000 == 0 {
+			snapshots = append(snapshots, h.Snapshot())
+		}
+	}
+
+	close(jobs)
+
+	h.Wg.Wait()
+	close(resultChan)
+
+	assert.Equal(t, uint64(len(vecs)), h.Seq())
+
+	for _, s := range snapshots {
+
+		// Every insert up to Seq is contained, node 0 is the initial entry-point
+		assert.Equal(t, int(s.Seq)+1, len(s.Nodes))
+		assert.Equal(t, s.Seq, s.Meta.Seq)
+
+		assert.Less(t, int(s.Meta.Ep), len(s.Nodes))
+
+		// No links to nodes inserted after the snapshot was taken
+		for _, node := range s.Nodes {
+			for level := 0; level <= node.Layer && level < len(node.Connections); level++ {
+				for _, id := range node.Connections[level] {
+					assert.Less(t, int(id), len(s.Nodes))
+				}
+			}
+		}
+	}
+
+}
+
+func Test_SnapshotSaveLoad(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(500, 8)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := 0; i < len(vecs); i++ {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "vector.gob")
+
+	s := h.Snapshot()
+
+	// Inserts after the snapshot must not be visible in the saved index
+	_, err = h.Insert(vecs[0])
+	assert.Nil(t, err)
+
+	err = s.Save(filename)
+	assert.Nil(t, err)
+
+	h2, err := hnsw.Load(filename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, uint64(len(vecs)), h2.Seq())
+	assert.Equal(t, len(vecs)+1, len(h2.NodeList.Nodes))
+	assert.Equal(t, h.M, h2.M)
+	assert.Equal(t, s.Meta.Ep, h2.Ep)
+	assert.Equal(t, s.Meta.Maxlevel, h2.Maxlevel)
+
+	assert.Equal(t, vecs[10], h2.PeekNode(11).Vectors)
+
+}
@@ -0,0 +1,133 @@
+package hnsw
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Number of links held by the nodes of a level
+type DegreeStats struct {
+	Min       int     `json:"min"`
+	Max       int     `json:"max"`
+	Avg       float64 `json:"avg"`
+	Histogram []int   `json:"histogram"` // Histogram[d] is the number of nodes with d links
+}
+
+// Nodes and links of a level, a node is on every level up to its top layer
+type LevelStats struct {
+	Level  int         `json:"level"`
+	Nodes  int         `json:"nodes"`
+	Edges  int         `json:"edges"` // Directed links from the nodes of the level
+	Degree DegreeStats `json:"degree"`
+}
+
+// Parameters and shape of the graph, returned by HNSW.Stats
+type GraphStats struct {
+	M              int     `json:"m"`
+	Mmax           int     `json:"mmax"`
+	Mmax0          int     `json:"mmax0"`
+	Efconstruction int     `json:"ef_construction"`
+	Ml             float64 `json:"ml"`
+	Heuristic      bool    `json:"heuristic"`
+	VectorType     string  `json:"vector_type"`
+	Quantization   string  `json:"quantization"`
+
+	Ep       int64 `json:"ep"`
+	MaxLevel int   `json:"max_level"`
+
+	Nodes   int          `json:"nodes"` // Including deleted nodes and the entry point placeholder
+	Deleted int          `json:"deleted"`
+	Levels  []LevelStats `json:"levels"` // Indexed by level, 0 to MaxLevel
+	Memory  int64        `json:"memory"` // Bytes, see HNSW.Memory
+}
+
+// Node counts and degree distribution of each level, safe to call while inserts are running
+func (h *HNSW) Stats() (stats GraphStats) {
+
+	stats.M = h.M
+	stats.Mmax = h.Mmax
+	stats.Mmax0 = h.Mmax0
+	stats.Efconstruction = h.Efconstruction
+	stats.Ml = h.Ml
+	stats.Heuristic = h.Heuristic
+	stats.VectorType = h.VectorType.String()
+	stats.Quantization = h.Quantization.String()
+
+	h.mutex.RLock()
+	stats.Ep = h.Ep
+	stats.MaxLevel = h.Maxlevel
+	h.mutex.RUnlock()
+
+	h.NodeList.mutex.RLock()
+
+	l := &h.NodeList
+
+	stats.Nodes = len(l.Nodes)
+
+	for i := range l.Nodes {
+
+		node := &l.Nodes[i]
+
+		if node.Deleted {
+			stats.Deleted++
+		}
+
+		// A node being inserted may be above the max level until the entry point is updated
+		for len(stats.Levels) <= node.Layer {
+			stats.Levels = append(stats.Levels, LevelStats{Level: len(stats.Levels), Degree: DegreeStats{Min: -1}})
+		}
+
+		for level := 0; level <= node.Layer; level++ {
+
+			degree := len(l.links(uint32(i), level))
+			s := &stats.Levels[level]
+
+			s.Nodes++
+			s.Edges += degree
+
+			if s.Degree.Min < 0 || degree < s.Degree.Min {
+				s.Degree.Min = degree
+			}
+
+			if degree > s.Degree.Max {
+				s.Degree.Max = degree
+			}
+
+			for len(s.Degree.Histogram) <= degree {
+				s.Degree.Histogram = append(s.Degree.Histogram, 0)
+			}
+
+			s.Degree.Histogram[degree]++
+
+		}
+
+	}
+
+	h.NodeList.mutex.RUnlock()
+
+	for i := range stats.Levels {
+		stats.Levels[i].Degree.Avg = float64(stats.Levels[i].Edges) / float64(max(1, stats.Levels[i].Nodes))
+	}
+
+	stats.Memory = h.Memory()
+
+	return
+
+}
+
+// Summary of the parameters and each level, one per line
+func (s GraphStats) String() string {
+
+	b := strings.Builder{}
+
+	fmt.Fprintf(&b, "M = %d, Mmax = %d, Mmax0 = %d, Efconstruction = %d, Ml = %f, Heuristic = %v\n", s.M, s.Mmax, s.Mmax0, s.Efconstruction, s.Ml, s.Heuristic)
+	fmt.Fprintf(&b, "Ep = %d, Maxlevel = %d\n", s.Ep, s.MaxLevel)
+	fmt.Fprintf(&b, "Nodes = %d, deleted = %d, memory %0.2f MB\n", s.Nodes, s.Deleted, float64(s.Memory)/(1<<20))
+
+	for _, level := range s.Levels {
+		fmt.Fprintf(&b, "\tLevel %d, nodes %d, edges %d, degree min %d avg %0.2f max %d\n", level.Level, level.Nodes, level.Edges, level.Degree.Min, level.Degree.Avg, level.Degree.Max)
+	}
+
+	return b.String()
+
+}
@@ -0,0 +1,90 @@
+package hnsw_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Stats(t *testing.T) {
+
+	h, err := hnsw.New(8, 8, 16, 100, 4)
+	assert.Nil(t, err)
+
+	// The last node is counted, linked to the entry point placeholder
+	_, err = h.Insert([]float32{1, 2, 3, 4})
+	assert.Nil(t, err)
+
+	stats := h.Stats()
+
+	assert.Equal(t, 2, stats.Nodes)
+	assert.Equal(t, 2, stats.Levels[0].Nodes)
+	assert.Equal(t, 2, stats.Levels[0].Edges)
+	assert.Equal(t, []int{0, 2}, stats.Levels[0].Degree.Histogram)
+
+	vecs, err := vectors.GenerateRandomVectors(2000, 16)
+	assert.Nil(t, err)
+
+	h, err = hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, h.Delete(10))
+	assert.Nil(t, h.Delete(20))
+
+	stats = h.Stats()
+
+	assert.Equal(t, 8, stats.M)
+	assert.Equal(t, 16, stats.Mmax0)
+	assert.Equal(t, h.Ep, stats.Ep)
+	assert.Equal(t, h.Maxlevel, stats.MaxLevel)
+	assert.Equal(t, 2001, stats.Nodes)
+	assert.Equal(t, 2, stats.Deleted)
+	assert.Equal(t, h.Memory(), stats.Memory)
+	assert.Equal(t, stats.MaxLevel+1, len(stats.Levels))
+	assert.Equal(t, stats.Nodes, stats.Levels[0].Nodes)
+
+	for i, level := range stats.Levels {
+
+		assert.Equal(t, i, level.Level)
+
+		if i > 0 {
+			assert.LessOrEqual(t, level.Nodes, stats.Levels[i-1].Nodes)
+			assert.LessOrEqual(t, level.Degree.Max, stats.Mmax)
+		} else {
+			assert.LessOrEqual(t, level.Degree.Max, stats.Mmax0)
+		}
+
+		assert.LessOrEqual(t, float64(level.Degree.Min), level.Degree.Avg)
+		assert.LessOrEqual(t, level.Degree.Avg, float64(level.Degree.Max))
+
+		// The histogram accounts for every node and edge
+		nodes, edges := 0, 0
+
+		for degree, count := range level.Degree.Histogram {
+			nodes += count
+			edges += degree * count
+		}
+
+		assert.Equal(t, level.Nodes, nodes)
+		assert.Equal(t, level.Edges, edges)
+
+	}
+
+	buf, err := json.Marshal(stats)
+	assert.Nil(t, err)
+
+	decoded := hnsw.GraphStats{}
+	assert.Nil(t, json.Unmarshal(buf, &decoded))
+	assert.Equal(t, stats, decoded)
+
+	assert.Contains(t, stats.String(), "Level 0, nodes 2001")
+
+}
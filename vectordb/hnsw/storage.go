@@ -0,0 +1,174 @@
+package hnsw
+
+import (
+	"fmt"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+)
+
+// Precision used to store each node's vector, queries are always float32
+type VectorType int
+
+const (
+	VectorFloat32  VectorType = iota
+	VectorFloat16             // IEEE 754 half precision, Node.Half
+	VectorBFloat16            // bfloat16 (upper 16 bits of a float32), Node.Half
+)
+
+func (t VectorType) String() string {
+
+	switch t {
+	case VectorFloat16:
+		return "float16"
+	case VectorBFloat16:
+		return "bfloat16"
+	}
+
+	return "float32"
+
+}
+
+// Store vectors as float16 or bfloat16 to halve the memory used, distances are computed converting on the fly
+func WithVectorType(t VectorType) Option {
+
+	return func(h *HNSW) error {
+		h.VectorType = t
+		return nil
+	}
+
+}
+
+// Vector of node `id` as float32, converted if stored as float16 or bfloat16 (nil once vectors are released). A float32
+// vector is a view of the index arena and must not be modified
+func (h *HNSW) Vector(id uint32) []float32 {
+
+	switch h.VectorType {
+	case VectorFloat16:
+		return vectors.FromFloat16(h.NodeList.halfVector(id))
+	case VectorBFloat16:
+		return vectors.FromBFloat16(h.NodeList.halfVector(id))
+	}
+
+	return h.NodeList.vector(id)
+
+}
+
+// Private functions
+
+func nodeVector(t VectorType, node *Node) []float32 {
+
+	switch t {
+	case VectorFloat16:
+		if node.Half != nil {
+			return vectors.FromFloat16(node.Half)
+		}
+	case VectorBFloat16:
+		if node.Half != nil {
+			return vectors.FromBFloat16(node.Half)
+		}
+	}
+
+	return node.Vectors
+
+}
+
+// Set the node's vector in the index's precision
+func (h *HNSW) storeVector(node *Node, v []float32) {
+
+	switch h.VectorType {
+	case VectorFloat16:
+		node.Half = vectors.ToFloat16(v)
+	case VectorBFloat16:
+		node.Half = vectors.ToBFloat16(v)
+	default:
+		node.Vectors = v
+	}
+
+}
+
+// Distance from the query to node `id`, the query length is checked by checkDimension before a search or insert
+func (h *HNSW) distanceTo(q *[]float32, id uint32) (nodeDist float32) {
+
+	var err error
+
+	switch h.VectorType {
+	case VectorFloat16:
+		v := h.NodeList.halfVector(id)
+		nodeDist, err = distance.L2_Float16(q, &v)
+	case VectorBFloat16:
+		v := h.NodeList.halfVector(id)
+		nodeDist, err = distance.L2_BFloat16(q, &v)
+	default:
+		v := h.NodeList.vector(id)
+		nodeDist, err = distance.L2_Opt(q, &v)
+	}
+
+	// A query that was not checked, a distance of 0 would silently make every node a match
+	if err != nil {
+		panic(err)
+	}
+
+	return nodeDist
+
+}
+
+// Distance between nodes `a` and `b`
+func (h *HNSW) distanceBetween(a uint32, b uint32) (nodeDist float32) {
+
+	var err error
+
+	switch h.VectorType {
+	case VectorFloat16:
+		va, vb := h.NodeList.halfVector(a), h.NodeList.halfVector(b)
+		nodeDist, err = distance.L2_Float16x2(&va, &vb)
+	case VectorBFloat16:
+		va, vb := h.NodeList.halfVector(a), h.NodeList.halfVector(b)
+		nodeDist, err = distance.L2_BFloat16x2(&va, &vb)
+	default:
+		va, vb := h.NodeList.vector(a), h.NodeList.vector(b)
+		nodeDist, err = distance.L2_Opt(&va, &vb)
+	}
+
+	// Every stored vector has the index dimension
+	if err != nil {
+		panic(err)
+	}
+
+	return nodeDist
+
+}
+
+// Return an error if `q` does not have the dimension of the index's vectors
+func (h *HNSW) checkDimension(q []float32) error {
+
+	dim := h.dimension()
+
+	if len(q) != dim {
+		return fmt.Errorf("vector has %d dimensions, the index has %d", len(q), dim)
+	}
+
+	return nil
+
+}
+
+// Dimension of the index's vectors
+func (h *HNSW) dimension() int {
+
+	if h.NodeList.dim > 0 {
+		return h.NodeList.dim
+	}
+
+	// Loaded after the vectors were released, only the quantizer has the dimension
+	switch h.Quantization {
+	case QuantizationInt8:
+		return len(h.ScalarQuantizer.Min)
+	case QuantizationPQ:
+		return h.ProductQuantizer.M * h.ProductQuantizer.SubDim
+	case QuantizationBinary:
+		return len(h.BinaryQuantizer.Threshold)
+	}
+
+	return 0
+
+}
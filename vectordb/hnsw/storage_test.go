@@ -0,0 +1,185 @@
+package hnsw_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Recall of Search on `h` against the exact K-NN from `exact` (float32), for each vector
+func exactRecall(t *testing.T, h *hnsw.HNSW, exact *hnsw.HNSW, vecs [][]float32, K int, efSearch int) float64 {
+
+	hits := 0
+
+	for i := range vecs {
+
+		truth, err := exact.BruteSearch(&vecs[i], K)
+		assert.Nil(t, err)
+
+		expected := make(map[uint32]bool)
+
+		for _, item := range truth.Sorted() {
+			expected[item.Node] = true
+		}
+
+		bestCandidates := queue.NewTopK(K)
+
+		err = h.Search(&vecs[i], bestCandidates, efSearch)
+		assert.Nil(t, err)
+
+		for _, item := range bestCandidates.Sorted() {
+			if expected[item.Node] {
+				hits++
+			}
+		}
+
+	}
+
+	return float64(hits) / float64(len(vecs)*K)
+
+}
+
+func Test_HalfPrecisionStorage(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(2000, 32)
+	assert.Nil(t, err)
+
+	exact, err := hnsw.New(16, 16, 32, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := exact.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	recall := exactRecall(t, &exact, &exact, vecs[:200], 10, 50)
+
+	for _, vectorType := range []hnsw.VectorType{hnsw.VectorFloat16, hnsw.VectorBFloat16} {
+
+		h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]), hnsw.WithVectorType(vectorType))
+		assert.Nil(t, err)
+
+		for i := range vecs {
+			_, err := h.Insert(vecs[i])
+			assert.Nil(t, err)
+		}
+
+		assert.Nil(t, h.PeekNode(1).Vectors)
+		assert.Equal(t, 32, len(h.PeekNode(1).Half))
+		assert.Equal(t, 32, len(h.Vector(0)))
+
+		for i, v := range h.Vector(1) {
+			assert.InDelta(t, vecs[0][i], v, 1e-2)
+		}
+
+		// Half the memory of float32
+		vectorBytes, _ := h.VectorMemory()
+		exactBytes, _ := exact.VectorMemory()
+		assert.Equal(t, exactBytes/2, vectorBytes)
+
+		// Total memory includes the vectors and links
+		assert.Greater(t, h.Memory(), vectorBytes)
+		assert.Less(t, h.Memory(), exact.Memory())
+
+		// Recall parity with float32, against the float32 ground truth
+		assert.GreaterOrEqual(t, exactRecall(t, &h, &exact, vecs[:200], 10, 50), recall-0.05, vectorType.String())
+
+		filename := filepath.Join(t.TempDir(), "vector.gob")
+
+		err = h.Save(filename)
+		assert.Nil(t, err)
+
+		h2, err := hnsw.Load(filename)
+		assert.Nil(t, err)
+
+		assert.Equal(t, vectorType, h2.VectorType)
+		assert.Equal(t, h.PeekNode(10).Half, h2.PeekNode(10).Half)
+
+	}
+
+}
+
+func Test_HalfPrecisionQuantized(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(1000, 32)
+	assert.Nil(t, err)
+
+	// Rerank from float16 vectors
+	h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]), hnsw.WithVectorType(hnsw.VectorFloat16), hnsw.WithScalarQuantizer(vecs[:200]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	assert.GreaterOrEqual(t, searchRecall(t, &h, vecs[:100], 10, 100), 0.95)
+
+}
+
+func Test_VectorDimension(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(200, 16)
+	assert.Nil(t, err)
+
+	for _, vectorType := range []hnsw.VectorType{hnsw.VectorFloat32, hnsw.VectorFloat16, hnsw.VectorBFloat16} {
+
+		h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]), hnsw.WithVectorType(vectorType))
+		assert.Nil(t, err)
+
+		for i := range vecs {
+			_, err := h.Insert(vecs[i])
+			assert.Nil(t, err)
+		}
+
+		// Rejected before anything is stored or a distance is computed
+		for _, q := range [][]float32{vecs[0][:8], append(vecs[0], 1)} {
+
+			_, err = h.Insert(q)
+			assert.NotNil(t, err, vectorType.String())
+
+			_, err = h.InsertTenant(q, 1)
+			assert.NotNil(t, err, vectorType.String())
+
+			assert.NotNil(t, h.Search(&q, queue.NewTopK(10), 50), vectorType.String())
+			assert.NotNil(t, h.SearchTenant(&q, queue.NewTopK(10), 50, 1), vectorType.String())
+
+			_, err = h.SearchTrace(&q, queue.NewTopK(10), 50)
+			assert.NotNil(t, err, vectorType.String())
+
+			_, err = h.BruteSearch(&q, 10)
+			assert.NotNil(t, err, vectorType.String())
+
+		}
+
+		assert.Equal(t, len(vecs)+1, h.Len())
+
+	}
+
+	// The dimension of an index saved once its vectors are released comes from the quantizer
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]), hnsw.WithScalarQuantizer(vecs))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, h.ReleaseVectors(nil))
+
+	filename := filepath.Join(t.TempDir(), "vector.gob")
+	assert.Nil(t, h.Save(filename))
+
+	h2, err := hnsw.Load(filename)
+	assert.Nil(t, err)
+
+	assert.Nil(t, h2.Search(&vecs[0], queue.NewTopK(10), 50))
+
+	q := vecs[0][:8]
+	assert.NotNil(t, h2.Search(&q, queue.NewTopK(10), 50))
+
+}
@@ -0,0 +1,271 @@
+package hnsw
+
+import (
+	"errors"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+)
+
+// Tenants share a single graph, each node records its tenant and SearchTenant only returns the nodes of the tenant
+// searched. Nodes of other tenants are still traversed on level 0 so the graph stays connected for every tenant, tenants
+// too small for the graph to find ef of their nodes quickly are scanned instead.
+//
+// A traversal visits about ef nodes for each of the tenant's nodes it finds, so finding ef of a tenant holding `live` of
+// the index's N nodes visits about ef*N/live nodes, most of the graph for a sparse tenant. A scan computes `live`
+// distances, so tenants with live*live <= ef*N are scanned, as are tenants with up to HNSW.TenantBruteForce vectors.
+
+var errNoTenant = errors.New("tenant 0 is reserved for nodes inserted without a tenant")
+
+// Nodes of a tenant, in insert order
+type tenant struct {
+	nodes []uint32 // Including deleted nodes, until DeleteTenant
+	live  int      // Nodes not deleted
+}
+
+// Insert `q` owned by `tenant`, returned only by SearchTenant for the same tenant (or Search)
+func (h *HNSW) InsertTenant(q []float32, tenant uint32) (uint32, error) {
+
+	if tenant == 0 {
+		return 0, errNoTenant
+	}
+
+	return h.insert(q, tenant)
+
+}
+
+// Find the nearest nodes of `tenant` to query point `q`, collected in `results` as Search does. Tenants with up to
+// TenantBruteForce vectors, or too sparse in the index for a traversal to be faster, are scanned. Other tenants are
+// searched with a level 0 traversal that only collects the tenant's nodes
+func (h *HNSW) SearchTenant(q *[]float32, results *queue.TopK, efSearch int, tenant uint32) (err error) {
+
+	if tenant == 0 {
+		return errNoTenant
+	}
+
+	err = h.checkDimension(*q)
+
+	if err != nil {
+		return err
+	}
+
+	var probe *searchProbe
+
+	if h.metrics != nil {
+		probe = newSearchProbe()
+	}
+
+	h.NodeList.mutex.RLock()
+
+	t, ok := h.NodeList.tenants[tenant]
+	live, scanned := 0, 0
+
+	if ok {
+		live = t.live
+	}
+
+	ef := max(efSearch, results.K())
+
+	// Scanning costs `live` distances, a traversal about ef*N/live
+	scan := live > 0 && !h.VectorsReleased && (live <= h.TenantBruteForce || int64(live)*int64(live) <= int64(ef)*int64(len(h.NodeList.Nodes)))
+
+	// The tenant's nodes are scanned under the lock, inserts only append to the list
+	if scan {
+
+		for _, id := range t.nodes {
+			if !h.NodeList.Nodes[id].Deleted {
+				results.Push(queue.Item{Node: id, Distance: h.distanceTo(q, id)})
+				scanned++
+			}
+		}
+
+	}
+
+	h.NodeList.mutex.RUnlock()
+
+	if live == 0 || scan {
+
+		if probe != nil {
+			probe.observeScan(h.metrics, scanned)
+		}
+
+		return nil
+
+	}
+
+	// Traverse the graph using quantized vectors if enabled, the upper layers are shared by all tenants
+	dist := h.queryDistance(q)
+
+	if probe != nil {
+		dist = probe.wrap(dist)
+	}
+
+	currentObj := &h.NodeList.Nodes[h.Ep]
+	match, currentDist, err := h.findEp(dist, currentObj, nil)
+
+	if err != nil {
+		return err
+	}
+
+	ctx := getSearchContext(len(h.NodeList.Nodes))
+	defer putSearchContext(ctx)
+
+	top := &ctx.top
+	top.Reset()
+
+	// Tenants never change once inserted, a node deleted during the search may still be returned as in Search
+	allowed := func(id uint32) bool {
+		node := &h.NodeList.Nodes[id]
+		return node.Tenant == tenant && !node.Deleted
+	}
+
+	if probe != nil {
+		probe.startLevel0()
+	}
+
+	h.searchLayerFiltered(ctx, dist, queue.Item{Node: match.Id, Distance: currentDist}, top, ef, allowed)
+
+	if h.Quantization != QuantizationNone {
+
+		err = h.rerank(q, top)
+
+		if err != nil {
+			return err
+		}
+
+	}
+
+	for _, item := range top.Items() {
+		results.Push(item)
+	}
+
+	if probe != nil {
+		probe.observe(h.metrics)
+	}
+
+	return nil
+
+}
+
+// Mark every node of `tenant` deleted, returns the number deleted
+func (h *HNSW) DeleteTenant(tenant uint32) (deleted int, err error) {
+
+	if tenant == 0 {
+		return 0, errNoTenant
+	}
+
+	// Block while a snapshot is being taken, as Delete does
+	h.writeGate.RLock()
+	defer h.writeGate.RUnlock()
+
+	h.NodeList.mutex.Lock()
+	defer h.NodeList.mutex.Unlock()
+
+	t, ok := h.NodeList.tenants[tenant]
+
+	if !ok {
+		return 0, nil
+	}
+
+	for _, id := range t.nodes {
+
+		if !h.NodeList.Nodes[id].Deleted {
+			h.NodeList.Nodes[id].Deleted = true
+			deleted++
+		}
+
+	}
+
+	delete(h.NodeList.tenants, tenant)
+
+	return deleted, nil
+
+}
+
+// Number of vectors of `tenant` not deleted
+func (h *HNSW) TenantLen(tenant uint32) int {
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	if t, ok := h.NodeList.tenants[tenant]; ok {
+		return t.live
+	}
+
+	return 0
+
+}
+
+// Private functions
+
+// Record node `id` of tenant `tenantId`, the caller must hold the mutex
+func (l *NodeList) addTenantNode(tenantId uint32, id uint32, deleted bool) {
+
+	t, ok := l.tenants[tenantId]
+
+	if !ok {
+		t = &tenant{}
+		l.tenants[tenantId] = t
+	}
+
+	t.nodes = append(t.nodes, id)
+
+	if !deleted {
+		t.live++
+	}
+
+}
+
+// Search level 0 as searchLayer, collecting only the nodes `allowed` in `topCandidates`. Other nodes are traversed but
+// take no place in the results, as hnswlib's filtered search, so the search only stops early once ef allowed nodes are
+// found
+func (h *HNSW) searchLayerFiltered(ctx *searchContext, dist queryDistance, ep queue.Item, topCandidates *queue.MaxHeap, ef int, allowed func(id uint32) bool) {
+
+	visited := &ctx.visited
+	visited.reset()
+	visited.visit(ep.Node)
+
+	candidates := &ctx.candidates
+	candidates.Reset()
+	candidates.Push(ep)
+
+	if allowed(ep.Node) {
+		topCandidates.Push(ep)
+	}
+
+	for candidates.Len() > 0 {
+
+		candidate, _ := candidates.Pop()
+
+		lowerBound, _ := topCandidates.Top()
+
+		if topCandidates.Len() >= ef && candidate.Distance > lowerBound.Distance {
+			break
+		}
+
+		for _, node := range h.NodeList.links(candidate.Node, 0) {
+
+			if visited.visit(node) {
+				continue
+			}
+
+			nodeDist := dist(node)
+
+			top, _ := topCandidates.Top()
+
+			if topCandidates.Len() < ef || nodeDist < top.Distance {
+
+				item := queue.Item{Node: node, Distance: nodeDist}
+
+				candidates.Push(item)
+
+				if allowed(node) {
+					topCandidates.PushBounded(item, ef)
+				}
+
+			}
+
+		}
+
+	}
+
+}
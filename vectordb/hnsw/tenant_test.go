@@ -0,0 +1,219 @@
+package hnsw_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Recall of SearchTenant for each vector of `tenant` against a scan of the tenant's nodes, checking every result belongs
+// to the tenant
+func tenantRecall(t *testing.T, h *hnsw.HNSW, vecs [][]float32, tenants []uint32, tenant uint32, K int) float64 {
+
+	hits, total := 0, 0
+
+	for i := range vecs {
+
+		if tenants[i] != tenant {
+			continue
+		}
+
+		exact := queue.NewTopK(K)
+
+		for id := 1; id < len(h.NodeList.Nodes); id++ {
+
+			node := h.PeekNode(id)
+
+			if node.Tenant == tenant && !node.Deleted {
+				exact.Push(queue.Item{Node: uint32(id), Distance: distanceL2(vecs[i], node.Vectors)})
+			}
+
+		}
+
+		expected := make(map[uint32]bool)
+
+		for _, item := range exact.Sorted() {
+			expected[item.Node] = true
+		}
+
+		results := queue.NewTopK(K)
+		assert.Nil(t, h.SearchTenant(&vecs[i], results, 50, tenant))
+
+		for _, item := range results.Sorted() {
+
+			assert.Equal(t, tenant, h.PeekNode(int(item.Node)).Tenant)
+
+			if expected[item.Node] {
+				hits++
+			}
+
+		}
+
+		total += exact.Len()
+
+	}
+
+	return float64(hits) / float64(total)
+
+}
+
+func distanceL2(a []float32, b []float32) (dist float32) {
+
+	for i := range a {
+		dist += (a[i] - b[i]) * (a[i] - b[i])
+	}
+
+	return
+
+}
+
+func Test_Tenant(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(3000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	h.TenantBruteForce = 200
+
+	// Tenant 1 is searched through the graph, tenant 2 is scanned, the rest have no tenant
+	tenants := make([]uint32, len(vecs))
+
+	for i := range vecs {
+
+		switch {
+		case i%3 == 0:
+			tenants[i] = 1
+		case i%30 == 1:
+			tenants[i] = 2
+		}
+
+		if tenants[i] == 0 {
+			_, err = h.Insert(vecs[i])
+		} else {
+			_, err = h.InsertTenant(vecs[i], tenants[i])
+		}
+
+		assert.Nil(t, err)
+
+	}
+
+	assert.Equal(t, 1000, h.TenantLen(1))
+	assert.Equal(t, 100, h.TenantLen(2))
+	assert.Equal(t, 0, h.TenantLen(3))
+
+	_, err = h.InsertTenant(vecs[0], 0)
+	assert.NotNil(t, err)
+	assert.NotNil(t, h.SearchTenant(&vecs[0], queue.NewTopK(1), 50, 0))
+
+	assert.GreaterOrEqual(t, tenantRecall(t, &h, vecs[:300], tenants, 1, 10), 0.9)
+	assert.Equal(t, 1.0, tenantRecall(t, &h, vecs[:300], tenants, 2, 10))
+
+	// A query near another tenant's vector still returns K of the tenant
+	results := queue.NewTopK(10)
+	assert.Nil(t, h.SearchTenant(&vecs[0], results, 50, 2))
+	assert.Equal(t, 10, results.Len())
+
+	results = queue.NewTopK(10)
+	assert.Nil(t, h.SearchTenant(&vecs[0], results, 50, 3))
+	assert.Equal(t, 0, results.Len())
+
+	// Node ids follow insert order after the entry point placeholder, vecs[1] is node 2 of tenant 2
+	assert.Nil(t, h.Delete(2))
+	assert.Equal(t, 99, h.TenantLen(2))
+
+	results = queue.NewTopK(1)
+	assert.Nil(t, h.SearchTenant(&vecs[1], results, 50, 2))
+	assert.NotEqual(t, uint32(2), results.Sorted()[0].Node)
+
+	deleted, err := h.DeleteTenant(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 99, deleted)
+	assert.Equal(t, 0, h.TenantLen(2))
+
+	results = queue.NewTopK(10)
+	assert.Nil(t, h.SearchTenant(&vecs[31], results, 50, 2))
+	assert.Equal(t, 0, results.Len())
+
+	results = queue.NewTopK(10)
+	assert.Nil(t, h.Search(&vecs[31], results, 50))
+
+	for _, item := range results.Sorted() {
+		assert.NotEqual(t, uint32(2), h.PeekNode(int(item.Node)).Tenant)
+	}
+
+	// Tenants are saved with the index
+	filename := filepath.Join(t.TempDir(), "tenant.hnsw")
+	assert.Nil(t, h.Save(filename))
+
+	h2, err := hnsw.Load(filename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1000, h2.TenantLen(1))
+	assert.Equal(t, 0, h2.TenantLen(2))
+
+	h2.TenantBruteForce = 200
+	assert.GreaterOrEqual(t, tenantRecall(t, &h2, vecs[:300], tenants, 1, 10), 0.9)
+
+}
+
+func Test_TenantSparse(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(6000, 16)
+	assert.Nil(t, err)
+
+	m := &recordedMetrics{}
+
+	h, err := hnsw.New(16, 16, 32, 100, len(vecs[0]), hnsw.WithMetrics(m))
+	assert.Nil(t, err)
+
+	h.TenantBruteForce = 100
+
+	// Tenant 1 holds 5% of the index and tenant 2 almost a third, both above TenantBruteForce
+	tenants := make([]uint32, len(vecs))
+
+	for i := range vecs {
+
+		switch {
+		case i%20 == 0:
+			tenants[i] = 1
+		case i%3 == 1:
+			tenants[i] = 2
+		}
+
+		if tenants[i] == 0 {
+			_, err = h.Insert(vecs[i])
+		} else {
+			_, err = h.InsertTenant(vecs[i], tenants[i])
+		}
+
+		assert.Nil(t, err)
+
+	}
+
+	assert.Equal(t, 300, h.TenantLen(1))
+	assert.Equal(t, 1900, h.TenantLen(2))
+
+	// 300*300 <= 50*6001, a traversal would visit about 1000 nodes, the scan computes 300 distances
+	assert.Nil(t, h.SearchTenant(&vecs[0], queue.NewTopK(10), 50, 1))
+	assert.Equal(t, 300, m.distances[len(m.distances)-1])
+	assert.Equal(t, 300, m.visited[len(m.visited)-1])
+
+	assert.Equal(t, 1.0, tenantRecall(t, &h, vecs[:600], tenants, 1, 10))
+
+	// Dense enough to traverse, the upper layers add distances but no visits on level 0
+	assert.Nil(t, h.SearchTenant(&vecs[1], queue.NewTopK(10), 50, 2))
+	assert.Greater(t, m.distances[len(m.distances)-1], m.visited[len(m.visited)-1])
+
+	assert.GreaterOrEqual(t, tenantRecall(t, &h, vecs[:300], tenants, 2, 10), 0.9)
+
+	// A larger efSearch scans a larger share
+	assert.Nil(t, h.SearchTenant(&vecs[1], queue.NewTopK(10), 700, 2))
+	assert.Equal(t, 1900, m.visited[len(m.visited)-1])
+
+}
@@ -0,0 +1,88 @@
+#!/usr/bin/env python3
+"""
+Generate hnswlib_l2.bin with the hnswlib package, and hnswlib_l2.json holding what the Go tests check against it.
+
+The index is saved by hnswlib save_index, then loaded back with load_index to check the file round-trips in hnswlib
+itself before the expected neighbours are recorded. One element is marked deleted.
+
+    pip install hnswlib numpy
+    python3 hnswlib_fixture.py
+
+An index exported by ExportHnswlib can be checked against hnswlib the same way, loaded and searched by hnswlib.
+
+    python3 hnswlib_fixture.py --check export.bin DIM
+"""
+
+import json
+import sys
+
+import hnswlib
+import numpy as np
+
+DIM = 8
+NUM = 200
+M = 8
+EF_CONSTRUCTION = 50
+EF = NUM  # Exhaustive, the neighbours do not depend on the graph
+K = 5
+DELETED = 7
+
+
+def check(filename, dim):
+
+    index = hnswlib.Index(space="l2", dim=dim)
+    index.load_index(filename)
+    index.set_ef(index.get_current_count())
+
+    labels = index.get_ids_list()
+    vectors = np.asarray(index.get_items(labels), dtype=np.float32)
+    found, _ = index.knn_query(vectors, k=1)
+
+    # Deleted elements are not returned, every other element is its own nearest neighbour
+    live = [i for i, label in enumerate(labels) if found[i][0] == label]
+    print("%s: %d elements, %d found themselves" % (filename, len(labels), len(live)))
+
+
+def main():
+
+    if len(sys.argv) == 4 and sys.argv[1] == "--check":
+        check(sys.argv[2], int(sys.argv[3]))
+        return
+
+    rng = np.random.default_rng(7)
+    vectors = rng.random((NUM, DIM), dtype=np.float32)
+    labels = np.arange(NUM, dtype=np.uint64) + 1000
+    queries = rng.random((10, DIM), dtype=np.float32)
+
+    index = hnswlib.Index(space="l2", dim=DIM)
+    index.init_index(max_elements=NUM, M=M, ef_construction=EF_CONSTRUCTION, random_seed=100)
+    index.add_items(vectors, labels, num_threads=1)
+    index.mark_deleted(int(labels[DELETED]))
+    index.save_index("hnswlib_l2.bin")
+
+    loaded = hnswlib.Index(space="l2", dim=DIM)
+    loaded.load_index("hnswlib_l2.bin")
+    loaded.set_ef(EF)
+    index.set_ef(EF)
+
+    neighbours, _ = loaded.knn_query(queries, k=K)
+    expected, _ = index.knn_query(queries, k=K)
+    assert (neighbours == expected).all(), "hnswlib does not load back its own index"
+    assert loaded.get_current_count() == NUM
+
+    with open("hnswlib_l2.json", "w") as f:
+        json.dump({
+            "dim": DIM,
+            "m": M,
+            "ef_construction": EF_CONSTRUCTION,
+            "ef": EF,
+            "labels": [int(label) for label in labels],
+            "vectors": vectors.tolist(),
+            "deleted": int(labels[DELETED]),
+            "queries": queries.tolist(),
+            "neighbours": neighbours.astype(np.int64).tolist(),
+        }, f)
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,101 @@
+#!/usr/bin/env python3
+"""
+Write hnswlib_layout.bin, a 6 element index laid out field by field as hnswlib HierarchicalNSW::saveIndex writes it,
+without depending on hnswlib or on ExportHnswlib. Test_ImportHnswlibLayout checks the import against the values below.
+
+Unlike ExportHnswlib output the file has spare capacity (max_elements above the element count), stale ids in the
+unused link slots, labels unrelated to the element order and one element marked deleted, as files saved by hnswlib do.
+
+    python3 hnswlib_layout.py
+
+hnswlib_fixture.py generates a larger fixture with hnswlib itself.
+"""
+
+import math
+import struct
+
+DIM = 2
+MAX_ELEMENTS = 8
+M = 2
+MAX_M = M
+MAX_M0 = M * 2
+EF_CONSTRUCTION = 16
+STALE = 0xDEADBEEF  # Left in unused link slots
+
+# Vector, label, top level and deleted flag of each element
+ELEMENTS = [
+    ((0.0, 0.0), 10, 1, False),
+    ((1.0, 0.0), 11, 0, False),
+    ((0.0, 1.0), 12, 2, False),
+    ((1.0, 1.0), 13, 0, True),
+    ((2.0, 0.0), 14, 1, False),
+    ((2.0, 2.0), 15, 0, False),
+]
+
+ENTERPOINT = 2
+MAXLEVEL = 2
+
+# Links of each element per level, level 0 first
+LINKS = [
+    [[1, 2, 3], [2, 4]],
+    [[0, 3, 4]],
+    [[0, 3, 5], [0, 4], []],
+    [[1, 2, 5, 4]],
+    [[1, 3, 5], [0, 2]],
+    [[3, 4, 2]],
+]
+
+DELETE_MARK = 0x01
+
+
+def link_list(links, max_links, deleted=False):
+
+    # Count in the first 2 bytes, the deleted mark in the 3rd
+    header = len(links) | ((DELETE_MARK if deleted else 0) << 16)
+
+    return struct.pack("<I", header) + struct.pack("<%dI" % max_links, *(links + [STALE] * (max_links - len(links))))
+
+
+def main():
+
+    size_links_level0 = MAX_M0 * 4 + 4
+    size_links_per_element = MAX_M * 4 + 4
+    offset_data = size_links_level0
+    label_offset = offset_data + DIM * 4
+    size_data_per_element = label_offset + 8
+
+    out = struct.pack(
+        "<QQQQQQiIQQQdQ",
+        0,  # offsetLevel0_
+        MAX_ELEMENTS,
+        len(ELEMENTS),
+        size_data_per_element,
+        label_offset,
+        offset_data,
+        MAXLEVEL,
+        ENTERPOINT,
+        MAX_M,
+        MAX_M0,
+        M,
+        1 / math.log(M),
+        EF_CONSTRUCTION,
+    )
+
+    for i, (vector, label, _, deleted) in enumerate(ELEMENTS):
+        out += link_list(LINKS[i][0], MAX_M0, deleted)
+        out += struct.pack("<%df" % DIM, *vector)
+        out += struct.pack("<Q", label)
+
+    for i, (_, _, level, _) in enumerate(ELEMENTS):
+
+        out += struct.pack("<I", level * size_links_per_element)
+
+        for links in LINKS[i][1:level + 1]:
+            out += link_list(links, MAX_M)
+
+    with open("hnswlib_layout.bin", "wb") as f:
+        f.write(out)
+
+
+if __name__ == "__main__":
+    main()
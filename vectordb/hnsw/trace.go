@@ -0,0 +1,104 @@
+package hnsw
+
+import (
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+)
+
+// Move of the greedy search of an upper level to a nearer node
+type TraceHop struct {
+	Level    int     `json:"level"`
+	Node     uint32  `json:"node"`
+	Distance float32 `json:"distance"`
+}
+
+// Neighbour of an expanded candidate visited for the first time
+type TraceNeighbour struct {
+	Node     uint32  `json:"node"`
+	Distance float32 `json:"distance"`
+	Accepted bool    `json:"accepted"` // Nearer than the furthest result (or fewer than ef results), added to the candidates
+	Bound    float32 `json:"bound"`    // Distance of the furthest result when evaluated
+}
+
+// Expansion of a candidate on level 0, the search computes the distance to each of its neighbours not yet visited
+type TraceExpansion struct {
+	Node       uint32           `json:"node"`
+	Distance   float32          `json:"distance"`
+	LowerBound float32          `json:"lower_bound"` // Distance of the furthest result when the candidate was expanded
+	Links      int              `json:"links"`
+	Evaluated  []TraceNeighbour `json:"evaluated"`
+}
+
+// Early stop of the level 0 search, the nearest candidate left is further than every result
+type TraceStop struct {
+	Node       uint32  `json:"node"`
+	Distance   float32 `json:"distance"`
+	LowerBound float32 `json:"lower_bound"`
+}
+
+// Record of a search, returned by SearchTrace
+type Trace struct {
+	Ep         uint32           `json:"ep"`
+	EpDistance float32          `json:"ep_distance"`
+	MaxLevel   int              `json:"max_level"`
+	Path       []TraceHop       `json:"path"`       // Greedy path through the upper levels to the level 0 entry point
+	Expansions []TraceExpansion `json:"expansions"` // Candidates expanded on level 0, in order
+	Stop       *TraceStop       `json:"stop"`       // Nil if the candidates were exhausted
+	Visited    int              `json:"visited"`    // Nodes visited on level 0, including the entry point
+	Distances  int              `json:"distances"`  // Distance computations on every level
+	Reranked   bool             `json:"reranked"`   // Quantized distances were replaced by exact distances
+}
+
+// Search as Search does, recording the path through the upper levels and each expansion on level 0. Slower than Search,
+// meant to explain why a query misses a neighbour, see Trace.Evaluated
+func (h *HNSW) SearchTrace(q *[]float32, results *queue.TopK, efSearch int) (trace *Trace, err error) {
+
+	trace = &Trace{Path: []TraceHop{}, Expansions: []TraceExpansion{}}
+
+	err = h.search(q, results, efSearch, trace)
+
+	return
+
+}
+
+// The level 0 evaluation of node `id`, false if the search never computed its distance on level 0, i.e it is not
+// linked from an expanded candidate. An evaluated node that was not accepted was further than the furthest result
+func (t *Trace) Evaluated(id uint32) (neighbour TraceNeighbour, ok bool) {
+
+	for _, expansion := range t.Expansions {
+
+		for _, neighbour := range expansion.Evaluated {
+			if neighbour.Node == id {
+				return neighbour, true
+			}
+		}
+
+	}
+
+	return
+
+}
+
+// Returns true if node `id` was expanded on level 0, an accepted node that was not expanded was left by the early stop
+func (t *Trace) Expanded(id uint32) bool {
+
+	for _, expansion := range t.Expansions {
+		if expansion.Node == id {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// Private functions
+
+// Count the distance computations of `dist`
+func (t *Trace) wrap(dist queryDistance) queryDistance {
+
+	return func(id uint32) float32 {
+		t.Distances++
+		return dist(id)
+	}
+
+}
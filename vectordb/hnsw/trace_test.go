@@ -0,0 +1,179 @@
+package hnsw_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SearchTrace(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(2000, 16)
+	assert.Nil(t, err)
+
+	m := &recordedMetrics{}
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]), hnsw.WithMetrics(m))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	queries, err := vectors.GenerateRandomVectors(20, 16)
+	assert.Nil(t, err)
+
+	for i := range queries {
+
+		expected := queue.NewTopK(10)
+		assert.Nil(t, h.Search(&queries[i], expected, 20))
+
+		results := queue.NewTopK(10)
+		trace, err := h.SearchTrace(&queries[i], results, 20)
+		assert.Nil(t, err)
+
+		// Same results as Search, and the same work as measured by the metrics
+		assert.Equal(t, expected.Sorted(), results.Sorted())
+		assert.Equal(t, m.distances[len(m.distances)-1], trace.Distances)
+		assert.Equal(t, m.visited[len(m.visited)-1], trace.Visited)
+
+		assert.Equal(t, uint32(h.Ep), trace.Ep)
+		assert.Equal(t, h.Maxlevel, trace.MaxLevel)
+
+		// The greedy path descends the levels, each hop nearer to the query
+		ep, epDistance := trace.Ep, float32(0)
+
+		for j, hop := range trace.Path {
+
+			assert.GreaterOrEqual(t, h.NodeList.Nodes[hop.Node].Layer, hop.Level)
+
+			if j > 0 {
+				assert.LessOrEqual(t, hop.Level, trace.Path[j-1].Level)
+				assert.Less(t, hop.Distance, trace.Path[j-1].Distance)
+			}
+
+			ep, epDistance = hop.Node, hop.Distance
+
+		}
+
+		if len(trace.Path) > 0 {
+			assert.Equal(t, epDistance, trace.EpDistance)
+		}
+
+		assert.Equal(t, ep, trace.Expansions[0].Node)
+		assert.Equal(t, trace.EpDistance, trace.Expansions[0].Distance)
+
+		visited := 1
+
+		for _, expansion := range trace.Expansions {
+
+			assert.LessOrEqual(t, len(expansion.Evaluated), expansion.Links)
+			visited += len(expansion.Evaluated)
+
+			for _, neighbour := range expansion.Evaluated {
+				if !neighbour.Accepted {
+					assert.GreaterOrEqual(t, neighbour.Distance, neighbour.Bound)
+				}
+			}
+
+		}
+
+		assert.Equal(t, visited, trace.Visited)
+		assert.GreaterOrEqual(t, trace.Distances, trace.Visited)
+
+		if trace.Stop != nil {
+			assert.Greater(t, trace.Stop.Distance, trace.Stop.LowerBound)
+			assert.False(t, trace.Expanded(trace.Stop.Node))
+		}
+
+		// Each result is the entry point or was accepted when evaluated
+		for _, item := range results.Sorted() {
+
+			if item.Node == ep {
+				continue
+			}
+
+			neighbour, ok := trace.Evaluated(item.Node)
+
+			assert.True(t, ok)
+			assert.True(t, neighbour.Accepted)
+			assert.Equal(t, item.Distance, neighbour.Distance)
+
+		}
+
+		assert.False(t, trace.Reranked)
+
+	}
+
+	// Found by Search, not evaluated
+	_, ok := (&hnsw.Trace{}).Evaluated(1)
+	assert.False(t, ok)
+
+	trace, err := h.SearchTrace(&queries[0], queue.NewTopK(10), 20)
+	assert.Nil(t, err)
+
+	buf, err := json.Marshal(trace)
+	assert.Nil(t, err)
+
+	decoded := hnsw.Trace{}
+	assert.Nil(t, json.Unmarshal(buf, &decoded))
+	assert.Equal(t, *trace, decoded)
+
+}
+
+func Test_FindEpGreedyWalk(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(5000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	assert.Greater(t, h.Maxlevel, 0)
+
+	queries, err := vectors.GenerateRandomVectors(200, 16)
+	assert.Nil(t, err)
+
+	for i := range queries {
+
+		trace, err := h.SearchTrace(&queries[i], queue.NewTopK(10), 20)
+		assert.Nil(t, err)
+
+		// Each hop is a link of the entry point or of a node reached before it
+		reached := []uint32{trace.Ep}
+
+		for _, hop := range trace.Path {
+
+			links := []uint32{}
+
+			for _, id := range reached {
+				links = append(links, h.PeekNode(int(id)).Connections[hop.Level]...)
+			}
+
+			assert.Contains(t, links, hop.Node)
+			reached = append(reached, hop.Node)
+
+		}
+
+		// The walk moves on to the links of each nearer node, stopping on level 1 once none is nearer
+		match, currentDist, err := h.FindEp(&queries[i], &h.NodeList.Nodes[h.Ep], 0)
+		assert.Nil(t, err)
+		assert.Equal(t, reached[len(reached)-1], match.Id)
+
+		for _, link := range h.PeekNode(int(match.Id)).Connections[1] {
+			assert.GreaterOrEqual(t, distanceL2(queries[i], h.Vector(link)), currentDist)
+		}
+
+	}
+
+}
@@ -0,0 +1,542 @@
+package hnsw
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+)
+
+// Kind of problem found by Validate
+type IssueKind int
+
+const (
+	IssueMissingNode IssueKind = iota // Link to a node that does not exist
+	IssueLevel                        // Link on a level above the node's layer, or to a node not on the level
+	IssueDegree                       // More links than Mmax (Mmax0 on level 0)
+	IssueSelfLoop                     // Link from a node to itself
+	IssueDuplicate                    // Same link more than once
+	IssueUnreachable                  // Node not reachable from the entry point on a level it is on
+)
+
+func (k IssueKind) String() string {
+
+	switch k {
+	case IssueMissingNode:
+		return "missing_node"
+	case IssueLevel:
+		return "level"
+	case IssueDegree:
+		return "degree"
+	case IssueSelfLoop:
+		return "self_loop"
+	case IssueDuplicate:
+		return "duplicate"
+	case IssueUnreachable:
+		return "unreachable"
+	}
+
+	return fmt.Sprintf("IssueKind(%d)", int(k))
+
+}
+
+func (k IssueKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Problem with the links of Node on Level
+type Issue struct {
+	Kind  IssueKind `json:"kind"`
+	Node  uint32    `json:"node"`
+	Level int       `json:"level"`
+	Link  uint32    `json:"link"` // Offending link, for missing node, level, self-loop and duplicate issues
+}
+
+// Result of Validate, the graph is valid if no issues are found
+type ValidationReport struct {
+	Nodes       int     `json:"nodes"`
+	Issues      []Issue `json:"issues"`
+	Unreachable []int   `json:"unreachable"` // Nodes not reachable from the entry point, indexed by level
+}
+
+// Result of Repair, with the issues it could not fix
+type RepairReport struct {
+	LinksRemoved     int              `json:"links_removed"`     // Links to missing nodes, self-loops, duplicates and links off the level
+	NodesPruned      int              `json:"nodes_pruned"`      // Nodes whose links exceeded the degree bound, the nearest are kept
+	NodesReconnected int              `json:"nodes_reconnected"` // Unreachable nodes linked from their nearest reachable neighbours
+	Remaining        ValidationReport `json:"remaining"`
+}
+
+// Returns true if no issues were found
+func (r ValidationReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Number of issues of `kind`
+func (r ValidationReport) Count(kind IssueKind) (count int) {
+
+	for _, issue := range r.Issues {
+		if issue.Kind == kind {
+			count++
+		}
+	}
+
+	return
+
+}
+
+// Check the links of every node: each points to an existing node on the same level, the degree bounds hold, there are
+// no self-loops or duplicates, and every node is reachable from the entry point on each of its levels. Safe to call
+// while inserts are running, nodes being inserted may be reported as unreachable
+func (h *HNSW) Validate() ValidationReport {
+
+	h.mutex.RLock()
+	ep, maxLevel := h.Ep, h.Maxlevel
+	h.mutex.RUnlock()
+
+	h.NodeList.mutex.RLock()
+	defer h.NodeList.mutex.RUnlock()
+
+	return h.validate(ep, maxLevel)
+
+}
+
+// Fix the issues found by Validate: invalid links are removed, nodes over the degree bound keep their nearest links and
+// unreachable nodes are linked from their nearest reachable neighbours. Writers are paused during
+// the repair, the issues left are returned in the report
+func (h *HNSW) Repair() (report RepairReport, err error) {
+
+	// Pruning and reconnecting compute distances between nodes
+	if h.VectorsReleased {
+		return report, errors.New("cannot repair once vectors are released")
+	}
+
+	h.writeGate.Lock()
+	defer h.writeGate.Unlock()
+
+	h.mutex.RLock()
+	ep, maxLevel := h.Ep, h.Maxlevel
+	h.mutex.RUnlock()
+
+	h.NodeList.mutex.Lock()
+	defer h.NodeList.mutex.Unlock()
+
+	l := &h.NodeList
+
+	for i := range l.Nodes {
+
+		id := uint32(i)
+
+		for level := 0; level < max(1, len(l.Nodes[i].Connections)); level++ {
+
+			links := l.links(id, level)
+			valid := make([]uint32, 0, len(links))
+
+			for j, link := range links {
+
+				if level <= l.Nodes[i].Layer && link != id && int(link) < len(l.Nodes) && l.Nodes[link].Layer >= level && !contains(links[:j], link) {
+					valid = append(valid, link)
+				}
+
+			}
+
+			report.LinksRemoved += len(links) - len(valid)
+
+			if bound := h.maxDegree(level); len(valid) > bound {
+				valid = h.nearestLinks(id, valid, bound)
+				report.NodesPruned++
+			}
+
+			if len(valid) < len(links) {
+				l.setLinks(id, level, valid)
+			}
+
+		}
+
+	}
+
+	report.NodesReconnected = h.reconnectUnreachable(ep, maxLevel)
+
+	report.Remaining = h.validate(ep, maxLevel)
+
+	return
+
+}
+
+// Private functions
+
+// Validate with the node list locked
+func (h *HNSW) validate(ep int64, maxLevel int) (report ValidationReport) {
+
+	l := &h.NodeList
+
+	report.Nodes = len(l.Nodes)
+	report.Issues = []Issue{}
+	report.Unreachable = make([]int, maxLevel+1)
+
+	for i := range l.Nodes {
+
+		id := uint32(i)
+
+		for level := 0; level < max(1, len(l.Nodes[i].Connections)); level++ {
+
+			links := l.links(id, level)
+
+			if len(links) > h.maxDegree(level) {
+				report.Issues = append(report.Issues, Issue{Kind: IssueDegree, Node: id, Level: level})
+			}
+
+			for j, link := range links {
+
+				kind := IssueKind(-1)
+
+				switch {
+				case int(link) >= len(l.Nodes):
+					kind = IssueMissingNode
+				case level > l.Nodes[i].Layer || l.Nodes[link].Layer < level:
+					kind = IssueLevel
+				case link == id:
+					kind = IssueSelfLoop
+				case contains(links[:j], link):
+					kind = IssueDuplicate
+				}
+
+				if kind >= 0 {
+					report.Issues = append(report.Issues, Issue{Kind: kind, Node: id, Level: level, Link: link})
+				}
+
+			}
+
+		}
+
+	}
+
+	visited := make([]bool, len(l.Nodes))
+
+	for level := maxLevel; level >= 0; level-- {
+
+		for i := range visited {
+			visited[i] = false
+		}
+
+		h.reach(uint32(ep), level, visited)
+
+		for i := range l.Nodes {
+
+			if !visited[i] && l.Nodes[i].Layer >= level {
+				report.Issues = append(report.Issues, Issue{Kind: IssueUnreachable, Node: uint32(i), Level: level})
+				report.Unreachable[level]++
+			}
+
+		}
+
+	}
+
+	return
+
+}
+
+// Mark the nodes reachable from `from` on `level`, breadth first. Links to missing nodes are skipped
+func (h *HNSW) reach(from uint32, level int, visited []bool) {
+
+	l := &h.NodeList
+
+	if visited[from] {
+		return
+	}
+
+	visited[from] = true
+	next := []uint32{from}
+
+	for len(next) > 0 {
+
+		id := next[0]
+		next = next[1:]
+
+		for _, link := range l.links(id, level) {
+
+			if int(link) < len(visited) && !visited[link] {
+				visited[link] = true
+				next = append(next, link)
+			}
+
+		}
+
+	}
+
+}
+
+// Search `level` for the nearest neighbours of node `id` and link them both ways, see backLink. `incoming` holds the
+// number of links to each node on the level and is kept up to date. Returns false if no neighbour was found
+func (h *HNSW) reconnect(id uint32, level int, ep int64, maxLevel int, incoming []int) bool {
+
+	l := &h.NodeList
+
+	q := h.Vector(id)
+	dist := h.exactDistance(&q)
+
+	// Greedy search of the levels above for the nearest entry point
+	current := uint32(ep)
+	currentDist := dist(current)
+
+	for lc := maxLevel; lc > level; lc-- {
+
+		changed := true
+
+		for changed {
+			changed = false
+
+			for _, link := range l.links(current, lc) {
+
+				if linkDist := dist(link); linkDist < currentDist {
+					current, currentDist = link, linkDist
+					changed = true
+				}
+
+			}
+
+		}
+
+	}
+
+	ctx := getSearchContext(len(l.Nodes))
+	defer putSearchContext(ctx)
+
+	topCandidates := &ctx.top
+	topCandidates.Reset()
+
+	h.searchLayer(ctx, dist, queue.Item{Node: current, Distance: currentDist}, topCandidates, h.Efconstruction, uint(level))
+
+	// The node itself is unreachable, it can only be found if it is the entry point
+	candidates := queue.NewMaxHeap(topCandidates.Len())
+
+	for topCandidates.Len() > 0 {
+
+		candidate, _ := topCandidates.Pop()
+
+		if candidate.Node != id {
+			candidates.Push(candidate)
+		}
+
+	}
+
+	if candidates.Len() == 0 {
+		return false
+	}
+
+	if h.Heuristic {
+		h.SelectNeighboursHeuristic(candidates, h.M)
+	} else {
+		h.SelectNeighboursSimple(candidates, h.M)
+	}
+
+	neighbours := make([]uint32, candidates.Len())
+
+	// Popped furthest first, nearest at index 0
+	for i := candidates.Len() - 1; i >= 0; i-- {
+		candidate, _ := candidates.Pop()
+		neighbours[i] = candidate.Node
+	}
+
+	links := append([]uint32{}, l.links(id, level)...)
+
+	for _, neighbour := range neighbours {
+		if !contains(links, neighbour) {
+			links = append(links, neighbour)
+		}
+	}
+
+	if bound := h.maxDegree(level); len(links) > bound {
+		links = h.nearestLinks(id, links, bound)
+	}
+
+	h.setLinksCounted(id, level, links, incoming)
+
+	h.backLink(id, neighbours, level, incoming)
+
+	return true
+
+}
+
+// Link each node not reachable from the entry point from its nearest reachable neighbours, extending the reachable set
+// from it so nodes reachable through it are kept as is. Returns the number of nodes linked
+func (h *HNSW) reconnectUnreachable(ep int64, maxLevel int) (reconnected int) {
+
+	l := &h.NodeList
+
+	visited := make([]bool, len(l.Nodes))
+
+	for level := maxLevel; level >= 0; level-- {
+
+		for i := range visited {
+			visited[i] = false
+		}
+
+		h.reach(uint32(ep), level, visited)
+
+		incoming := h.incomingLinks(level)
+
+		for i := range l.Nodes {
+
+			if visited[i] || l.Nodes[i].Layer < level {
+				continue
+			}
+
+			if h.reconnect(uint32(i), level, ep, maxLevel, incoming) {
+				reconnected++
+			}
+
+			h.reach(uint32(i), level, visited)
+
+		}
+
+	}
+
+	return
+
+}
+
+// Link `neighbours` (nearest first) to node `id` on `level`, each neighbour with room for another link. If all are full
+// the furthest link of a neighbour is replaced, one whose node has other incoming links so it is not orphaned in turn
+func (h *HNSW) backLink(id uint32, neighbours []uint32, level int, incoming []int) {
+
+	l := &h.NodeList
+
+	linked := false
+
+	for _, neighbour := range neighbours {
+
+		links := l.links(neighbour, level)
+
+		if contains(links, id) {
+			linked = true
+			continue
+		}
+
+		if len(links) < h.maxDegree(level) {
+			h.setLinksCounted(neighbour, level, append(append([]uint32{}, links...), id), incoming)
+			linked = true
+		}
+
+	}
+
+	if linked || len(neighbours) == 0 {
+		return
+	}
+
+	for _, neighbour := range neighbours {
+
+		links := l.links(neighbour, level)
+		links = h.nearestLinks(neighbour, links, len(links))
+
+		for i := len(links) - 1; i >= 0; i-- {
+
+			if int(links[i]) < len(incoming) && incoming[links[i]] > 1 {
+				links[i] = id
+				h.setLinksCounted(neighbour, level, links, incoming)
+				return
+			}
+
+		}
+
+	}
+
+	// Every link is the only one to its node, one of them is orphaned
+	links := l.links(neighbours[0], level)
+	links = h.nearestLinks(neighbours[0], links, len(links))
+	links[len(links)-1] = id
+
+	h.setLinksCounted(neighbours[0], level, links, incoming)
+
+}
+
+// Number of links to each node on `level`
+func (h *HNSW) incomingLinks(level int) []int {
+
+	l := &h.NodeList
+
+	incoming := make([]int, len(l.Nodes))
+
+	for i := range l.Nodes {
+
+		if l.Nodes[i].Layer < level {
+			continue
+		}
+
+		for _, link := range l.links(uint32(i), level) {
+			if int(link) < len(incoming) {
+				incoming[link]++
+			}
+		}
+
+	}
+
+	return incoming
+
+}
+
+// Replace the links of node `id` on `level`, updating the incoming link counts
+func (h *HNSW) setLinksCounted(id uint32, level int, links []uint32, incoming []int) {
+
+	l := &h.NodeList
+
+	for _, link := range l.links(id, level) {
+		if int(link) < len(incoming) {
+			incoming[link]--
+		}
+	}
+
+	for _, link := range links {
+		if int(link) < len(incoming) {
+			incoming[link]++
+		}
+	}
+
+	l.setLinks(id, level, links)
+
+}
+
+// Links allowed per node on `level`
+func (h *HNSW) maxDegree(level int) int {
+
+	if level == 0 {
+		return h.Mmax0
+	}
+
+	return h.Mmax
+
+}
+
+// The `n` links of node `id` nearest to it, nearest first
+func (h *HNSW) nearestLinks(id uint32, links []uint32, n int) []uint32 {
+
+	topCandidates := queue.NewMaxHeap(len(links))
+
+	for _, link := range links {
+		topCandidates.Push(queue.Item{Node: link, Distance: h.distanceBetween(id, link)})
+	}
+
+	h.SelectNeighboursSimple(topCandidates, n)
+
+	nearest := make([]uint32, topCandidates.Len())
+
+	for i := len(nearest) - 1; i >= 0; i-- {
+		item, _ := topCandidates.Pop()
+		nearest[i] = item.Node
+	}
+
+	return nearest
+
+}
+
+func contains(links []uint32, id uint32) bool {
+
+	for _, link := range links {
+		if link == id {
+			return true
+		}
+	}
+
+	return false
+
+}
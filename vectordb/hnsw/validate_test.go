@@ -0,0 +1,125 @@
+package hnsw_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validate(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(2000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	report := h.Validate()
+
+	assert.Equal(t, 2001, report.Nodes)
+	assert.Equal(t, h.Maxlevel+1, len(report.Unreachable))
+
+	// Pruning may leave nodes unreachable, the links themselves are valid
+	assert.Equal(t, len(report.Issues), report.Count(hnsw.IssueUnreachable), report.Issues)
+
+	// Nodes on level 1, other than the entry point
+	upper := []uint32{}
+
+	for i := 1; i < len(h.NodeList.Nodes); i++ {
+		if h.NodeList.Nodes[i].Layer >= 1 && int64(i) != h.Ep {
+			upper = append(upper, uint32(i))
+		}
+	}
+
+	a, c := upper[0], upper[1]
+
+	// A missing node, a self-loop, a duplicate and more links than Mmax
+	links := append([]uint32{}, h.NodeList.Nodes[a].Connections[1]...)
+	links = append(links, 100000, a, links[0])
+
+	for _, id := range upper[2:] {
+
+		if len(links) > h.Mmax+4 {
+			break
+		}
+
+		links = append(links, id)
+
+	}
+
+	h.NodeList.Nodes[a].Connections[1] = links
+
+	// A link above the node's layer
+	for i := 1; i < len(h.NodeList.Nodes); i++ {
+
+		if h.NodeList.Nodes[i].Layer == 0 {
+			h.NodeList.Nodes[i].Connections[1] = []uint32{a}
+			break
+		}
+
+	}
+
+	// Node `c` unreachable on level 1
+	for i := range h.NodeList.Nodes {
+
+		if len(h.NodeList.Nodes[i].Connections) < 2 || uint32(i) == a {
+			continue
+		}
+
+		kept := []uint32{}
+
+		for _, link := range h.NodeList.Nodes[i].Connections[1] {
+			if link != c {
+				kept = append(kept, link)
+			}
+		}
+
+		h.NodeList.Nodes[i].Connections[1] = kept
+
+	}
+
+	report = h.Validate()
+
+	assert.False(t, report.Valid())
+
+	for _, kind := range []hnsw.IssueKind{hnsw.IssueMissingNode, hnsw.IssueLevel, hnsw.IssueDegree, hnsw.IssueSelfLoop, hnsw.IssueDuplicate, hnsw.IssueUnreachable} {
+		assert.GreaterOrEqual(t, report.Count(kind), 1, kind.String())
+	}
+
+	assert.Contains(t, report.Issues, hnsw.Issue{Kind: hnsw.IssueMissingNode, Node: a, Level: 1, Link: 100000})
+	assert.Contains(t, report.Issues, hnsw.Issue{Kind: hnsw.IssueSelfLoop, Node: a, Level: 1, Link: a})
+	assert.GreaterOrEqual(t, report.Unreachable[1], 1)
+
+	buf, err := json.Marshal(report.Issues[0])
+	assert.Nil(t, err)
+	assert.Contains(t, string(buf), `"kind":"`)
+
+	repair, err := h.Repair()
+	assert.Nil(t, err)
+
+	assert.GreaterOrEqual(t, repair.LinksRemoved, 4)
+	assert.Equal(t, 1, repair.NodesPruned)
+	assert.GreaterOrEqual(t, repair.NodesReconnected, 1)
+
+	assert.True(t, repair.Remaining.Valid(), repair.Remaining.Issues)
+	assert.True(t, h.Validate().Valid())
+
+	// Repaired nodes are found
+	for _, id := range []uint32{a, c} {
+
+		results := queue.NewTopK(1)
+		assert.Nil(t, h.Search(&vecs[id-1], results, 50))
+		assert.Equal(t, id, results.Sorted()[0].Node)
+
+	}
+
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+)
+
+// Bucket bounds of the latency histograms, 50us to 2.5s
+var LatencyBuckets = []float64{0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// Bucket bounds of the distance computation and visited node histograms, 16 to 65536
+var SearchWorkBuckets = ExponentialBuckets(16, 2, 13)
+
+// Metrics of an HNSW index, set with hnsw.WithMetrics or HNSW.SetMetrics
+type Index struct {
+	Inserts       *Counter
+	InsertLatency *Histogram
+
+	Searches      *Counter
+	SearchLatency *Histogram
+	Distances     *Histogram // Distance computations per search
+	Visited       *Histogram // Nodes visited on level 0 per search
+}
+
+// Register the metrics of `h` in `r`, with `labels` to tell indexes apart. The node count and max level are read from
+// `h` when the metrics are written, call h.SetMetrics with the returned metrics to measure inserts and searches
+func NewIndex(r *Registry, h *hnsw.HNSW, labels Labels) *Index {
+
+	r.GaugeFunc("hnsw_nodes", "Number of nodes in the graph, including deleted nodes and the entry point placeholder.", labels, func() float64 {
+		return float64(h.Len())
+	})
+
+	r.GaugeFunc("hnsw_max_level", "Top layer of the graph.", labels, func() float64 {
+		return float64(h.MaxLevel())
+	})
+
+	return &Index{
+		Inserts:       r.Counter("hnsw_inserts_total", "Vectors inserted.", labels),
+		InsertLatency: r.Histogram("hnsw_insert_duration_seconds", "Time taken to insert a vector.", LatencyBuckets, labels),
+		Searches:      r.Counter("hnsw_searches_total", "Searches of the graph, Search and SearchTenant.", labels),
+		SearchLatency: r.Histogram("hnsw_search_duration_seconds", "Time taken to search the graph.", LatencyBuckets, labels),
+		Distances:     r.Histogram("hnsw_search_distance_computations", "Distances computed to traverse the graph per search.", SearchWorkBuckets, labels),
+		Visited:       r.Histogram("hnsw_search_visited_nodes", "Nodes visited on level 0 per search.", SearchWorkBuckets, labels),
+	}
+
+}
+
+func (m *Index) ObserveInsert(latency time.Duration) {
+
+	m.Inserts.Inc()
+	m.InsertLatency.Observe(latency.Seconds())
+
+}
+
+func (m *Index) ObserveSearch(latency time.Duration, distances int, visited int) {
+
+	m.Searches.Inc()
+	m.SearchLatency.Observe(latency.Seconds())
+	m.Distances.Observe(float64(distances))
+	m.Visited.Observe(float64(visited))
+
+}
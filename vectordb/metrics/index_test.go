@@ -0,0 +1,70 @@
+package metrics_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/hnsw"
+	"github.com/aws-samples/gofast-hnsw/vectordb/metrics"
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Index(t *testing.T) {
+
+	vecs, err := vectors.GenerateRandomVectors(1000, 16)
+	assert.Nil(t, err)
+
+	h, err := hnsw.New(8, 8, 16, 100, len(vecs[0]))
+	assert.Nil(t, err)
+
+	r := metrics.NewRegistry()
+	m := metrics.NewIndex(r, &h, metrics.Labels{"index": "test"})
+
+	h.SetMetrics(m)
+
+	for i := range vecs {
+		_, err := h.Insert(vecs[i])
+		assert.Nil(t, err)
+	}
+
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, h.Search(&vecs[i], queue.NewTopK(10), 50))
+	}
+
+	assert.Equal(t, uint64(1000), m.Inserts.Value())
+	assert.Equal(t, uint64(1000), m.InsertLatency.Count())
+	assert.Equal(t, uint64(100), m.Searches.Value())
+	assert.Equal(t, uint64(100), m.Visited.Count())
+
+	// At least ef nodes are visited, each with a distance computed
+	assert.GreaterOrEqual(t, m.Visited.Sum(), float64(100*50))
+	assert.GreaterOrEqual(t, m.Distances.Sum(), m.Visited.Sum())
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, r.Write(&buf))
+
+	assert.Contains(t, buf.String(), `hnsw_nodes{index="test"} 1001`)
+	assert.Contains(t, buf.String(), `hnsw_inserts_total{index="test"} 1000`)
+	assert.Contains(t, buf.String(), `hnsw_searches_total{index="test"} 100`)
+	assert.Contains(t, buf.String(), `hnsw_search_visited_nodes_count{index="test"} 100`)
+	assert.Contains(t, buf.String(), `hnsw_max_level{index="test"} `)
+
+	// Imported from hnswlib, no entry point placeholder
+	filename := filepath.Join(t.TempDir(), "export.bin")
+	assert.Nil(t, h.ExportHnswlib(filename, nil))
+
+	h2, _, err := hnsw.ImportHnswlib(filename)
+	assert.Nil(t, err)
+
+	r2 := metrics.NewRegistry()
+	metrics.NewIndex(r2, &h2, metrics.Labels{"index": "imported"})
+
+	buf.Reset()
+	assert.Nil(t, r2.Write(&buf))
+
+	assert.Contains(t, buf.String(), `hnsw_nodes{index="imported"} 1000`)
+
+}
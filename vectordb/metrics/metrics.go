@@ -0,0 +1,350 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counters, gauges and histograms written in the Prometheus text exposition format, without the client library. Each
+// metric is registered once per set of labels and updated with atomics, the registry is only locked to register and
+// write metrics.
+
+// Constant labels of a series, e.g {"collection": "products"}
+type Labels map[string]string
+
+// Metrics exposed together, e.g on a /metrics endpoint
+type Registry struct {
+	mutex    sync.Mutex
+	families []*family // In registration order
+	byName   map[string]*family
+}
+
+// Metrics with the same name and different labels
+type family struct {
+	name   string
+	help   string
+	kind   string // counter, gauge or histogram
+	series map[string]writer
+}
+
+// A series, writes its samples with `labels` already formatted
+type writer interface {
+	write(w io.Writer, name string, labels string)
+}
+
+// Monotonically increasing count
+type Counter struct {
+	value atomic.Uint64
+}
+
+// Value read when the metrics are written
+type GaugeFunc func() float64
+
+// Distribution of observed values, in buckets of upper bounds
+type Histogram struct {
+	bounds []float64       // Upper bounds, increasing
+	counts []atomic.Uint64 // Observations per bucket (not cumulative), the last is +Inf
+	sum    atomic.Uint64   // float64 bits
+}
+
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]*family)}
+}
+
+// Register a counter, returns the existing counter if already registered with the same labels
+func (r *Registry) Counter(name string, help string, labels Labels) *Counter {
+	return r.register(name, help, "counter", labels, func() writer { return &Counter{} }).(*Counter)
+}
+
+// Register a gauge reading `fn`, replacing the function if already registered with the same labels
+func (r *Registry) GaugeFunc(name string, help string, labels Labels, fn func() float64) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	f := r.family(name, help, "gauge")
+	f.series[formatLabels(labels)] = GaugeFunc(fn)
+
+}
+
+// Register a histogram with the upper `bounds` of its buckets, returns the existing histogram if already registered
+// with the same labels
+func (r *Registry) Histogram(name string, help string, bounds []float64, labels Labels) *Histogram {
+
+	return r.register(name, help, "histogram", labels, func() writer {
+
+		bounds = append([]float64(nil), bounds...)
+		sort.Float64s(bounds)
+
+		return &Histogram{bounds: bounds, counts: make([]atomic.Uint64, len(bounds)+1)}
+
+	}).(*Histogram)
+
+}
+
+// Remove every series with `labels`, e.g once the index they measure is dropped
+func (r *Registry) Unregister(labels Labels) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := formatLabels(labels)
+
+	for _, f := range r.families {
+		delete(f.series, key)
+	}
+
+}
+
+// Write the metrics in the Prometheus text format, families in registration order and series sorted by labels
+func (r *Registry) Write(w io.Writer) error {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	buf := bufio.NewWriter(w)
+
+	for _, f := range r.families {
+
+		if len(f.series) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(buf, "# HELP %s %s\n", f.name, escape(f.help, false))
+		fmt.Fprintf(buf, "# TYPE %s %s\n", f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			f.series[key].write(buf, f.name, key)
+		}
+
+	}
+
+	return buf.Flush()
+
+}
+
+// Serve the metrics, for a /metrics endpoint
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	r.Write(w)
+
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+func (c *Counter) Add(n uint64) {
+	c.value.Add(n)
+}
+
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+func (h *Histogram) Observe(v float64) {
+
+	h.counts[sort.SearchFloat64s(h.bounds, v)].Add(1)
+
+	for {
+
+		old := h.sum.Load()
+
+		if h.sum.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+
+	}
+
+}
+
+// Number of observations
+func (h *Histogram) Count() (count uint64) {
+
+	for i := range h.counts {
+		count += h.counts[i].Load()
+	}
+
+	return
+
+}
+
+// Sum of the observations
+func (h *Histogram) Sum() float64 {
+	return math.Float64frombits(h.sum.Load())
+}
+
+// `count` bucket bounds starting at `start`, each `factor` times the previous
+func ExponentialBuckets(start float64, factor float64, count int) []float64 {
+
+	bounds := make([]float64, count)
+
+	for i := range bounds {
+		bounds[i] = start
+		start *= factor
+	}
+
+	return bounds
+
+}
+
+// Private functions
+
+// Get or create series `labels` of family `name`, created with `create`
+func (r *Registry) register(name string, help string, kind string, labels Labels, create func() writer) writer {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	f := r.family(name, help, kind)
+	key := formatLabels(labels)
+
+	if s, ok := f.series[key]; ok {
+		return s
+	}
+
+	s := create()
+	f.series[key] = s
+
+	return s
+
+}
+
+// Get or create family `name`, the caller must hold the mutex. Panics if registered with a different kind
+func (r *Registry) family(name string, help string, kind string) *family {
+
+	f, ok := r.byName[name]
+
+	if !ok {
+		f = &family{name: name, help: help, kind: kind, series: make(map[string]writer)}
+		r.byName[name] = f
+		r.families = append(r.families, f)
+	}
+
+	if f.kind != kind {
+		panic(fmt.Sprintf("metric %s registered as %s and %s", name, f.kind, kind))
+	}
+
+	return f
+
+}
+
+func (c *Counter) write(w io.Writer, name string, labels string) {
+	fmt.Fprintf(w, "%s%s %d\n", name, braces(labels), c.Value())
+}
+
+func (g GaugeFunc) write(w io.Writer, name string, labels string) {
+	fmt.Fprintf(w, "%s%s %s\n", name, braces(labels), formatFloat(g()))
+}
+
+func (h *Histogram) write(w io.Writer, name string, labels string) {
+
+	cumulative := uint64(0)
+
+	for i := range h.counts {
+
+		cumulative += h.counts[i].Load()
+
+		le := "+Inf"
+
+		if i < len(h.bounds) {
+			le = formatFloat(h.bounds[i])
+		}
+
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, braces(join(labels, `le="`+le+`"`)), cumulative)
+
+	}
+
+	// The count is the +Inf bucket, the sum may include observations made since the buckets were read
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, braces(labels), formatFloat(h.Sum()))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, braces(labels), cumulative)
+
+}
+
+// Labels as name="value" pairs sorted by name, without braces
+func formatLabels(labels Labels) string {
+
+	names := make([]string, 0, len(labels))
+
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+
+	for i, name := range names {
+		pairs[i] = name + `="` + escape(labels[name], true) + `"`
+	}
+
+	return strings.Join(pairs, ",")
+
+}
+
+func braces(labels string) string {
+
+	if labels == "" {
+		return ""
+	}
+
+	return "{" + labels + "}"
+
+}
+
+func join(labels string, label string) string {
+
+	if labels == "" {
+		return label
+	}
+
+	return labels + "," + label
+
+}
+
+// Escape backslashes and newlines, and double quotes in label values
+func escape(s string, quotes bool) string {
+
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+
+	if quotes {
+		s = strings.ReplaceAll(s, `"`, `\"`)
+	}
+
+	return s
+
+}
+
+func formatFloat(v float64) string {
+
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+
+}
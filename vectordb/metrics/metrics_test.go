@@ -0,0 +1,73 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Registry(t *testing.T) {
+
+	r := metrics.NewRegistry()
+
+	requests := r.Counter("requests_total", "Requests served.", metrics.Labels{"path": "/search"})
+	requests.Add(2)
+	requests.Inc()
+
+	// Registered again with the same labels, the same counter
+	assert.Equal(t, requests, r.Counter("requests_total", "Requests served.", metrics.Labels{"path": "/search"}))
+
+	r.Counter("requests_total", "Requests served.", metrics.Labels{"path": "/insert"}).Inc()
+
+	r.GaugeFunc("nodes", "Nodes in the graph.", nil, func() float64 { return 42 })
+
+	latency := r.Histogram("latency_seconds", "Request latency.", []float64{0.5, 0.1}, metrics.Labels{"name": `a"b\c`})
+
+	for _, v := range []float64{0.05, 0.1, 0.2, 3} {
+		latency.Observe(v)
+	}
+
+	assert.Equal(t, uint64(4), latency.Count())
+	assert.InDelta(t, 3.35, latency.Sum(), 1e-9)
+
+	expected := `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{path="/insert"} 1
+requests_total{path="/search"} 3
+# HELP nodes Nodes in the graph.
+# TYPE nodes gauge
+nodes 42
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{name="a\"b\\c",le="0.1"} 2
+latency_seconds_bucket{name="a\"b\\c",le="0.5"} 3
+latency_seconds_bucket{name="a\"b\\c",le="+Inf"} 4
+latency_seconds_sum{name="a\"b\\c"} 3.35
+latency_seconds_count{name="a\"b\\c"} 4
+`
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, r.Write(&buf))
+	assert.Equal(t, expected, buf.String())
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, expected, recorder.Body.String())
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "version=0.0.4")
+
+	// Families with no series left are not written
+	r.Unregister(metrics.Labels{"name": `a"b\c`})
+
+	buf.Reset()
+	assert.Nil(t, r.Write(&buf))
+	assert.NotContains(t, buf.String(), "latency_seconds")
+
+	assert.Panics(t, func() { r.GaugeFunc("requests_total", "", nil, func() float64 { return 0 }) })
+
+	assert.Equal(t, []float64{16, 32, 64}, metrics.ExponentialBuckets(16, 2, 3))
+
+}
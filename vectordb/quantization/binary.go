@@ -0,0 +1,77 @@
+package quantization
+
+import (
+	"errors"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+)
+
+// Binary (1-bit) quantizer, each dimension is set if the value is above the threshold for that dimension, packed 8
+// dimensions per byte. Distances between codes are the Hamming distance.
+type BinaryQuantizer struct {
+	Threshold []float32 // Per dimension, 0 for the sign of each value
+}
+
+// Sign quantizer, for embeddings centred on zero
+func NewBinaryQuantizer(dim int) *BinaryQuantizer {
+	return &BinaryQuantizer{Threshold: make([]float32, dim)}
+}
+
+// Train the thresholds as the mean of each dimension over the sample, so each bit splits the data evenly even if the
+// embeddings are not centred on zero
+func TrainBinaryQuantizer(sample [][]float32) (bq *BinaryQuantizer, err error) {
+
+	if len(sample) == 0 || len(sample[0]) == 0 {
+		return nil, errors.New("binary quantizer requires a non-empty training sample")
+	}
+
+	dim := len(sample[0])
+
+	sums := make([]float64, dim)
+
+	for _, v := range sample {
+
+		if len(v) != dim {
+			return nil, errors.New("training sample vectors must have the same dimension")
+		}
+
+		for i := range v {
+			sums[i] += float64(v[i])
+		}
+
+	}
+
+	bq = NewBinaryQuantizer(dim)
+
+	for i := range sums {
+		bq.Threshold[i] = float32(sums[i] / float64(len(sample)))
+	}
+
+	return bq, nil
+
+}
+
+// Encode a vector to 1 bit per dimension, padded to a multiple of 8 bytes so codes are compared 64 bits at a time
+func (bq *BinaryQuantizer) Encode(v []float32) (code []uint8) {
+
+	code = make([]uint8, bq.CodeSize())
+
+	for i := range v {
+		if v[i] > bq.Threshold[i] {
+			code[i/8] |= 1 << (i % 8)
+		}
+	}
+
+	return code
+
+}
+
+// Hamming distance between two encoded vectors
+func (bq *BinaryQuantizer) Distance(a []uint8, b []uint8) int {
+	return distance.HammingBytes(a, b)
+}
+
+// Bytes used to store each encoded vector
+func (bq *BinaryQuantizer) CodeSize() int {
+	return (len(bq.Threshold) + 63) / 64 * 8
+}
@@ -0,0 +1,40 @@
+package quantization_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/quantization"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BinaryQuantizer(t *testing.T) {
+
+	bq := quantization.NewBinaryQuantizer(10)
+
+	// Padded to 8 bytes
+	assert.Equal(t, 8, bq.CodeSize())
+
+	code := bq.Encode([]float32{1, -1, 0, 2, -3, 4, 5, -6, 7, 0.5})
+	assert.Equal(t, []uint8{0b01101001, 0b11, 0, 0, 0, 0, 0, 0}, code)
+
+	// Opposite signs differ in every non-zero dimension
+	code2 := bq.Encode([]float32{-1, 1, 0, -2, 3, -4, -5, 6, -7, -0.5})
+	assert.Equal(t, 9, bq.Distance(code, code2))
+	assert.Equal(t, 0, bq.Distance(code, code))
+
+	assert.Equal(t, 16, quantization.NewBinaryQuantizer(65).CodeSize())
+
+}
+
+func Test_TrainBinaryQuantizer(t *testing.T) {
+
+	bq, err := quantization.TrainBinaryQuantizer([][]float32{{0, 10}, {1, 20}})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []float32{0.5, 15}, bq.Threshold)
+	assert.Equal(t, uint8(0b10), bq.Encode([]float32{0, 16})[0])
+
+	_, err = quantization.TrainBinaryQuantizer(nil)
+	assert.NotNil(t, err)
+
+}
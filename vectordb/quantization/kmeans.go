@@ -0,0 +1,110 @@
+package quantization
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Lloyd's k-means, returns `k` centroids (flattened, k*dim) initialised from distinct random points. Empty clusters
+// are re-seeded from a random point.
+func kmeans(points [][]float32, k int, iterations int, rng *rand.Rand) (centroids []float32) {
+
+	dim := len(points[0])
+
+	centroids = make([]float32, k*dim)
+
+	for c, i := range rng.Perm(len(points))[:k] {
+		copy(centroids[c*dim:], points[i])
+	}
+
+	assignments := make([]int, len(points))
+	sums := make([]float64, k*dim)
+	counts := make([]int, k)
+
+	for iter := 0; iter < iterations; iter++ {
+
+		changed := false
+
+		for i, p := range points {
+
+			c := nearestCentroid(centroids, dim, p)
+
+			if c != assignments[i] || iter == 0 {
+				assignments[i] = c
+				changed = true
+			}
+
+		}
+
+		if !changed {
+			break
+		}
+
+		for i := range sums {
+			sums[i] = 0
+		}
+
+		for i := range counts {
+			counts[i] = 0
+		}
+
+		for i, p := range points {
+
+			c := assignments[i]
+			counts[c]++
+
+			for d := range p {
+				sums[c*dim+d] += float64(p[d])
+			}
+
+		}
+
+		for c := 0; c < k; c++ {
+
+			if counts[c] == 0 {
+				copy(centroids[c*dim:(c+1)*dim], points[rng.Intn(len(points))])
+				continue
+			}
+
+			for d := 0; d < dim; d++ {
+				centroids[c*dim+d] = float32(sums[c*dim+d] / float64(counts[c]))
+			}
+
+		}
+
+	}
+
+	return centroids
+
+}
+
+// Index of the centroid (flattened, k*dim) nearest to `v`
+func nearestCentroid(centroids []float32, dim int, v []float32) (nearest int) {
+
+	best := float32(math.MaxFloat32)
+
+	for c := 0; c*dim < len(centroids); c++ {
+
+		dist := squaredL2(v, centroids[c*dim:(c+1)*dim])
+
+		if dist < best {
+			best = dist
+			nearest = c
+		}
+
+	}
+
+	return nearest
+
+}
+
+func squaredL2(a []float32, b []float32) (distance float32) {
+
+	for i := range a {
+		d := a[i] - b[i]
+		distance += d * d
+	}
+
+	return distance
+
+}
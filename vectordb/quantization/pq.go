@@ -0,0 +1,145 @@
+package quantization
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Max centroids per subspace, each sub-vector is encoded to 1 byte
+const pqCentroids = 256
+
+// k-means iterations used to train each codebook
+const pqIterations = 25
+
+// Product quantizer, each vector is split into M subspaces and each sub-vector is replaced by the id of the nearest
+// centroid in that subspace's codebook, reducing each vector to M bytes
+type ProductQuantizer struct {
+	M         int         // Number of subspaces (bytes per encoded vector)
+	Ks        int         // Centroids per subspace (256, or fewer if the training sample is smaller)
+	SubDim    int         // Dimensions per subspace
+	Codebooks [][]float32 // Centroids per subspace, flattened (Ks*SubDim)
+}
+
+// Train a codebook per subspace with k-means, the vector dimension must be a multiple of `m`
+func TrainProductQuantizer(sample [][]float32, m int) (pq *ProductQuantizer, err error) {
+
+	if len(sample) == 0 || len(sample[0]) == 0 {
+		return nil, errors.New("product quantizer requires a non-empty training sample")
+	}
+
+	dim := len(sample[0])
+
+	if m <= 0 || dim%m != 0 {
+		return nil, fmt.Errorf("vector dimension (%d) must be a multiple of the number of subspaces (%d)", dim, m)
+	}
+
+	for _, v := range sample {
+		if len(v) != dim {
+			return nil, errors.New("training sample vectors must have the same dimension")
+		}
+	}
+
+	pq = &ProductQuantizer{
+		M:         m,
+		Ks:        pqCentroids,
+		SubDim:    dim / m,
+		Codebooks: make([][]float32, m),
+	}
+
+	if len(sample) < pq.Ks {
+		pq.Ks = len(sample)
+	}
+
+	// Subspaces are independent, train each codebook concurrently
+	var wg sync.WaitGroup
+
+	for s := 0; s < m; s++ {
+
+		wg.Add(1)
+
+		go func(s int) {
+
+			defer wg.Done()
+
+			points := make([][]float32, len(sample))
+
+			for i := range sample {
+				points[i] = sample[i][s*pq.SubDim : (s+1)*pq.SubDim]
+			}
+
+			// Seed per subspace, training is repeatable for the same sample
+			pq.Codebooks[s] = kmeans(points, pq.Ks, pqIterations, rand.New(rand.NewSource(int64(s+1))))
+
+		}(s)
+
+	}
+
+	wg.Wait()
+
+	return pq, nil
+
+}
+
+// Encode a vector to M bytes, the nearest centroid in each subspace
+func (pq *ProductQuantizer) Encode(v []float32) (code []uint8) {
+
+	code = make([]uint8, pq.M)
+
+	for s := range code {
+		code[s] = uint8(nearestCentroid(pq.Codebooks[s], pq.SubDim, v[s*pq.SubDim:(s+1)*pq.SubDim]))
+	}
+
+	return code
+
+}
+
+// Decode to the approximate vector, the concatenated centroids
+func (pq *ProductQuantizer) Decode(code []uint8) (v []float32) {
+
+	v = make([]float32, 0, pq.M*pq.SubDim)
+
+	for s, c := range code {
+		v = append(v, pq.Codebooks[s][int(c)*pq.SubDim:(int(c)+1)*pq.SubDim]...)
+	}
+
+	return v
+
+}
+
+// Build the asymmetric distance computation (ADC) lookup table for a query, the squared L2 distance from each query
+// sub-vector to every centroid in the subspace (M*Ks)
+func (pq *ProductQuantizer) DistanceTable(q []float32) (table []float32) {
+
+	table = make([]float32, pq.M*pq.Ks)
+
+	for s := 0; s < pq.M; s++ {
+
+		sub := q[s*pq.SubDim : (s+1)*pq.SubDim]
+
+		for c := 0; c < pq.Ks; c++ {
+			table[s*pq.Ks+c] = squaredL2(sub, pq.Codebooks[s][c*pq.SubDim:(c+1)*pq.SubDim])
+		}
+
+	}
+
+	return table
+
+}
+
+// Squared L2 distance between the query of a DistanceTable and an encoded vector
+func (pq *ProductQuantizer) TableDistance(table []float32, code []uint8) (distance float32) {
+
+	for s, c := range code {
+		distance += table[s*pq.Ks+int(c)]
+	}
+
+	return distance
+
+}
+
+// Bytes used to store each encoded vector
+func (pq *ProductQuantizer) CodeSize() int {
+	return pq.M
+}
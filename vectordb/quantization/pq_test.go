@@ -0,0 +1,85 @@
+package quantization_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/quantization"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ProductQuantizer(t *testing.T) {
+
+	sample, err := vectors.GenerateRandomVectors(2000, 32)
+	assert.Nil(t, err)
+
+	pq, err := quantization.TrainProductQuantizer(sample, 8)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 8, pq.CodeSize())
+	assert.Equal(t, 256, pq.Ks)
+	assert.Equal(t, 4, pq.SubDim)
+
+	// Training is repeatable
+	pq2, err := quantization.TrainProductQuantizer(sample, 8)
+	assert.Nil(t, err)
+	assert.Equal(t, pq.Codebooks, pq2.Codebooks)
+
+	var errSum, distSum float32
+
+	for i := 0; i < 100; i++ {
+
+		code := pq.Encode(sample[i])
+		assert.Equal(t, 8, len(code))
+
+		decoded := pq.Decode(code)
+		assert.Equal(t, 32, len(decoded))
+
+		// ADC distance is the exact distance from the query to the decoded vector
+		table := pq.DistanceTable(sample[i+1])
+		expected, _ := distance.L2_Opt(&sample[i+1], &decoded)
+		assert.InDelta(t, expected, pq.TableDistance(table, code), 1e-4)
+
+		reconstruction, _ := distance.L2_Opt(&sample[i], &decoded)
+		exact, _ := distance.L2_Opt(&sample[i], &sample[i+1])
+
+		errSum += reconstruction
+		distSum += exact
+
+	}
+
+	// Reconstruction error is small relative to the distance between vectors
+	assert.Less(t, errSum, distSum/4)
+
+}
+
+func Test_ProductQuantizerSmallSample(t *testing.T) {
+
+	sample := [][]float32{{0, 0, 1, 1}, {1, 1, 0, 0}, {0, 1, 0, 1}}
+
+	pq, err := quantization.TrainProductQuantizer(sample, 2)
+	assert.Nil(t, err)
+
+	// Fewer centroids than the sample size, each sample vector is its own centroid
+	assert.Equal(t, 3, pq.Ks)
+
+	for _, v := range sample {
+		assert.Equal(t, v, pq.Decode(pq.Encode(v)))
+	}
+
+}
+
+func Test_ProductQuantizerInvalid(t *testing.T) {
+
+	_, err := quantization.TrainProductQuantizer(nil, 2)
+	assert.NotNil(t, err)
+
+	// Dimension is not a multiple of m
+	_, err = quantization.TrainProductQuantizer([][]float32{{1, 2, 3}}, 2)
+	assert.NotNil(t, err)
+
+	_, err = quantization.TrainProductQuantizer([][]float32{{1, 2}, {1, 2, 3}}, 1)
+	assert.NotNil(t, err)
+
+}
@@ -0,0 +1,114 @@
+package quantization
+
+import (
+	"errors"
+	"math"
+)
+
+// Scalar (int8) quantizer, each dimension is mapped to 256 levels between the min and max of the training sample,
+// reducing each vector to 1 byte per dimension
+type ScalarQuantizer struct {
+	Min   []float32 // Min value per dimension
+	Scale []float32 // (max - min) / 255 per dimension
+}
+
+// Train the quantizer from a sample of vectors, values outside the sample range are clamped when encoded
+func TrainScalarQuantizer(sample [][]float32) (sq *ScalarQuantizer, err error) {
+
+	if len(sample) == 0 || len(sample[0]) == 0 {
+		return nil, errors.New("scalar quantizer requires a non-empty training sample")
+	}
+
+	dim := len(sample[0])
+
+	sq = &ScalarQuantizer{
+		Min:   make([]float32, dim),
+		Scale: make([]float32, dim),
+	}
+
+	max := make([]float32, dim)
+
+	copy(sq.Min, sample[0])
+	copy(max, sample[0])
+
+	for _, v := range sample {
+
+		if len(v) != dim {
+			return nil, errors.New("training sample vectors must have the same dimension")
+		}
+
+		for i := range v {
+			if v[i] < sq.Min[i] {
+				sq.Min[i] = v[i]
+			}
+			if v[i] > max[i] {
+				max[i] = v[i]
+			}
+		}
+	}
+
+	for i := range sq.Scale {
+		sq.Scale[i] = (max[i] - sq.Min[i]) / 255
+	}
+
+	return sq, nil
+
+}
+
+// Encode a vector to 1 byte per dimension
+func (sq *ScalarQuantizer) Encode(v []float32) (code []uint8) {
+
+	code = make([]uint8, len(v))
+
+	for i := range v {
+
+		// Constant dimension in the sample
+		if sq.Scale[i] == 0 {
+			continue
+		}
+
+		level := math.Round(float64((v[i] - sq.Min[i]) / sq.Scale[i]))
+
+		if level < 0 {
+			level = 0
+		} else if level > 255 {
+			level = 255
+		}
+
+		code[i] = uint8(level)
+
+	}
+
+	return code
+
+}
+
+// Decode to the approximate vector
+func (sq *ScalarQuantizer) Decode(code []uint8) (v []float32) {
+
+	v = make([]float32, len(code))
+
+	for i := range code {
+		v[i] = sq.Min[i] + float32(code[i])*sq.Scale[i]
+	}
+
+	return v
+
+}
+
+// Squared L2 distance between a full precision query and an encoded vector (asymmetric, only the vector is quantized)
+func (sq *ScalarQuantizer) Distance(q []float32, code []uint8) (distance float32) {
+
+	for i := range code {
+		d := q[i] - (sq.Min[i] + float32(code[i])*sq.Scale[i])
+		distance += d * d
+	}
+
+	return distance
+
+}
+
+// Bytes used to store each encoded vector
+func (sq *ScalarQuantizer) CodeSize() int {
+	return len(sq.Min)
+}
@@ -0,0 +1,64 @@
+package quantization_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/distance"
+	"github.com/aws-samples/gofast-hnsw/vectordb/quantization"
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScalarQuantizer(t *testing.T) {
+
+	sample, err := vectors.GenerateRandomVectors(1000, 32)
+	assert.Nil(t, err)
+
+	sq, err := quantization.TrainScalarQuantizer(sample)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 32, sq.CodeSize())
+
+	for _, v := range sample[:100] {
+
+		code := sq.Encode(v)
+		assert.Equal(t, 32, len(code))
+
+		// Each component is within half a quantization step
+		decoded := sq.Decode(code)
+
+		for i := range v {
+			assert.InDelta(t, v[i], decoded[i], float64(sq.Scale[i])/2+1e-6)
+		}
+
+	}
+
+	// Asymmetric distance approximates the exact distance
+	for i := 0; i < 100; i++ {
+
+		exact, _ := distance.L2_Opt(&sample[i], &sample[i+1])
+		approx := sq.Distance(sample[i], sq.Encode(sample[i+1]))
+
+		assert.InDelta(t, exact, approx, 0.05)
+
+	}
+
+}
+
+func Test_ScalarQuantizerClamp(t *testing.T) {
+
+	sq, err := quantization.TrainScalarQuantizer([][]float32{{0, 1}, {1, 1}})
+	assert.Nil(t, err)
+
+	// Out of range values clamp, constant dimensions encode to 0
+	assert.Equal(t, []uint8{0, 0}, sq.Encode([]float32{-5, 1}))
+	assert.Equal(t, []uint8{255, 0}, sq.Encode([]float32{5, 3}))
+	assert.Equal(t, []float32{1, 1}, sq.Decode([]uint8{255, 0}))
+
+	_, err = quantization.TrainScalarQuantizer(nil)
+	assert.NotNil(t, err)
+
+	_, err = quantization.TrainScalarQuantizer([][]float32{{0, 1}, {1}})
+	assert.NotNil(t, err)
+
+}
@@ -0,0 +1,297 @@
+package queue
+
+// Binary heaps of value Items, unlike PriorityQueue there is no container/heap interface boxing or *Item allocation,
+// so once grown a heap can be reused with Reset without allocating.
+
+// Min-heap, the nearest item (lowest distance) on top
+type MinHeap struct {
+	items []Item
+}
+
+// Max-heap, the furthest item (highest distance) on top. Used to hold the best k results, the worst is replaced first
+type MaxHeap struct {
+	items []Item
+}
+
+func NewMinHeap(capacity int) *MinHeap {
+	return &MinHeap{items: make([]Item, 0, capacity)}
+}
+
+func NewMaxHeap(capacity int) *MaxHeap {
+	return &MaxHeap{items: make([]Item, 0, capacity)}
+}
+
+func (h *MinHeap) Len() int { return len(h.items) }
+
+// Remove all items, keeping the capacity
+func (h *MinHeap) Reset() { h.items = h.items[:0] }
+
+// Items in heap order (unsorted), valid until the heap is modified
+func (h *MinHeap) Items() []Item { return h.items }
+
+// Re-establish the heap order, after the distances of Items() are modified in place
+func (h *MinHeap) Init() {
+
+	for i := len(h.items)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+
+}
+
+// Return the nearest item, false if the heap is empty
+func (h *MinHeap) Top() (Item, bool) {
+
+	if len(h.items) == 0 {
+		return Item{}, false
+	}
+
+	return h.items[0], true
+
+}
+
+func (h *MinHeap) Push(item Item) {
+
+	h.items = append(h.items, item)
+
+	// Sift up
+	i := len(h.items) - 1
+
+	for i > 0 {
+
+		parent := (i - 1) / 2
+
+		if h.items[parent].Distance <= h.items[i].Distance {
+			break
+		}
+
+		h.items[parent], h.items[i] = h.items[i], h.items[parent]
+		i = parent
+
+	}
+
+}
+
+// Remove and return the nearest item, false if the heap is empty
+func (h *MinHeap) Pop() (Item, bool) {
+
+	if len(h.items) == 0 {
+		return Item{}, false
+	}
+
+	top := h.items[0]
+	last := len(h.items) - 1
+
+	h.items[0] = h.items[last]
+	h.items = h.items[:last]
+	h.down(0)
+
+	return top, true
+
+}
+
+// Push then pop in a single sift, returns `item` itself if it is nearer than the top
+func (h *MinHeap) PushPop(item Item) Item {
+
+	if len(h.items) == 0 || item.Distance <= h.items[0].Distance {
+		return item
+	}
+
+	top := h.items[0]
+	h.items[0] = item
+	h.down(0)
+
+	return top
+
+}
+
+// Pop every item nearest first, appended to dst
+func (h *MinHeap) Drain(dst []Item) []Item {
+
+	for len(h.items) > 0 {
+		item, _ := h.Pop()
+		dst = append(dst, item)
+	}
+
+	return dst
+
+}
+
+func (h *MinHeap) down(i int) {
+
+	n := len(h.items)
+
+	for {
+
+		smallest := i
+		left := 2*i + 1
+		right := left + 1
+
+		if left < n && h.items[left].Distance < h.items[smallest].Distance {
+			smallest = left
+		}
+
+		if right < n && h.items[right].Distance < h.items[smallest].Distance {
+			smallest = right
+		}
+
+		if smallest == i {
+			return
+		}
+
+		h.items[i], h.items[smallest] = h.items[smallest], h.items[i]
+		i = smallest
+
+	}
+
+}
+
+func (h *MaxHeap) Len() int { return len(h.items) }
+
+// Remove all items, keeping the capacity
+func (h *MaxHeap) Reset() { h.items = h.items[:0] }
+
+// Items in heap order (unsorted), valid until the heap is modified
+func (h *MaxHeap) Items() []Item { return h.items }
+
+// Re-establish the heap order, after the distances of Items() are modified in place
+func (h *MaxHeap) Init() {
+
+	for i := len(h.items)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+
+}
+
+// Return the furthest item, false if the heap is empty
+func (h *MaxHeap) Top() (Item, bool) {
+
+	if len(h.items) == 0 {
+		return Item{}, false
+	}
+
+	return h.items[0], true
+
+}
+
+func (h *MaxHeap) Push(item Item) {
+
+	h.items = append(h.items, item)
+
+	// Sift up
+	i := len(h.items) - 1
+
+	for i > 0 {
+
+		parent := (i - 1) / 2
+
+		if h.items[parent].Distance >= h.items[i].Distance {
+			break
+		}
+
+		h.items[parent], h.items[i] = h.items[i], h.items[parent]
+		i = parent
+
+	}
+
+}
+
+// Remove and return the furthest item, false if the heap is empty
+func (h *MaxHeap) Pop() (Item, bool) {
+
+	if len(h.items) == 0 {
+		return Item{}, false
+	}
+
+	top := h.items[0]
+	last := len(h.items) - 1
+
+	h.items[0] = h.items[last]
+	h.items = h.items[:last]
+	h.down(0)
+
+	return top, true
+
+}
+
+// Push then pop in a single sift, returns `item` itself if it is further than the top
+func (h *MaxHeap) PushPop(item Item) Item {
+
+	if len(h.items) == 0 || item.Distance >= h.items[0].Distance {
+		return item
+	}
+
+	top := h.items[0]
+	h.items[0] = item
+	h.down(0)
+
+	return top
+
+}
+
+// Bounded top-k insert, keep the `k` nearest items. Returns false if the heap is full and `item` is no nearer than the
+// furthest
+func (h *MaxHeap) PushBounded(item Item, k int) bool {
+
+	if len(h.items) < k {
+		h.Push(item)
+		return true
+	}
+
+	if k == 0 || item.Distance >= h.items[0].Distance {
+		return false
+	}
+
+	h.items[0] = item
+	h.down(0)
+
+	return true
+
+}
+
+// Pop every item nearest first, appended to dst
+func (h *MaxHeap) Drain(dst []Item) []Item {
+
+	n := len(dst)
+
+	for len(h.items) > 0 {
+		item, _ := h.Pop()
+		dst = append(dst, item)
+	}
+
+	// Popped furthest first
+	for i, j := n, len(dst)-1; i < j; i, j = i+1, j-1 {
+		dst[i], dst[j] = dst[j], dst[i]
+	}
+
+	return dst
+
+}
+
+func (h *MaxHeap) down(i int) {
+
+	n := len(h.items)
+
+	for {
+
+		largest := i
+		left := 2*i + 1
+		right := left + 1
+
+		if left < n && h.items[left].Distance > h.items[largest].Distance {
+			largest = left
+		}
+
+		if right < n && h.items[right].Distance > h.items[largest].Distance {
+			largest = right
+		}
+
+		if largest == i {
+			return
+		}
+
+		h.items[i], h.items[largest] = h.items[largest], h.items[i]
+		i = largest
+
+	}
+
+}
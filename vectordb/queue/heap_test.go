@@ -0,0 +1,187 @@
+package queue_test
+
+import (
+	"container/heap"
+	"sort"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+// Distances of `items` in ascending order
+func sortedItems() []float32 {
+
+	sorted := append([]float32{}, items...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted
+
+}
+
+func Test_MinHeap(t *testing.T) {
+
+	h := queue.NewMinHeap(0)
+
+	_, ok := h.Top()
+	assert.False(t, ok)
+
+	_, ok = h.Pop()
+	assert.False(t, ok)
+
+	for k, v := range items {
+		h.Push(queue.Item{Node: uint32(k), Distance: v})
+	}
+
+	assert.Equal(t, 20, h.Len())
+
+	top, ok := h.Top()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(2), top.Node)
+	assert.Equal(t, float32(0.001), top.Distance)
+
+	// Nearer than the top is returned immediately
+	assert.Equal(t, float32(0.0001), h.PushPop(queue.Item{Distance: 0.0001}).Distance)
+
+	// Otherwise the top is replaced
+	assert.Equal(t, float32(0.001), h.PushPop(queue.Item{Node: 100, Distance: 100}).Distance)
+	assert.Equal(t, 20, h.Len())
+
+	sorted := sortedItems()[1:]
+	drained := h.Drain(nil)
+
+	assert.Equal(t, 0, h.Len())
+	assert.Equal(t, 20, len(drained))
+
+	for i := range sorted {
+		assert.Equal(t, sorted[i], drained[i].Distance)
+	}
+
+	assert.Equal(t, uint32(100), drained[19].Node)
+
+}
+
+func Test_MaxHeap(t *testing.T) {
+
+	h := queue.NewMaxHeap(0)
+
+	_, ok := h.Top()
+	assert.False(t, ok)
+
+	for k, v := range items {
+		h.Push(queue.Item{Node: uint32(k), Distance: v})
+	}
+
+	top, ok := h.Top()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(15), top.Node)
+	assert.Equal(t, float32(10.03), top.Distance)
+
+	item, ok := h.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, float32(10.03), item.Distance)
+
+	assert.Equal(t, float32(11), h.PushPop(queue.Item{Distance: 11}).Distance)
+	assert.Equal(t, float32(9), h.PushPop(queue.Item{Distance: 0.0001}).Distance)
+
+	// Modify in place, the nearest becomes the furthest
+	for i := range h.Items() {
+		if h.Items()[i].Distance == 0.0001 {
+			h.Items()[i].Distance = 20
+		}
+	}
+
+	h.Init()
+
+	top, _ = h.Top()
+	assert.Equal(t, float32(20), top.Distance)
+
+	item, _ = h.Pop()
+	item.Distance = 0.0001
+	h.Push(item)
+
+	drained := h.Drain(nil)
+	assert.Equal(t, 19, len(drained))
+	assert.Equal(t, float32(0.0001), drained[0].Distance)
+
+	for i := 1; i < len(drained); i++ {
+		assert.LessOrEqual(t, drained[i-1].Distance, drained[i].Distance)
+	}
+
+}
+
+func Test_MaxHeapBounded(t *testing.T) {
+
+	h := queue.NewMaxHeap(5)
+
+	for k, v := range items {
+		h.PushBounded(queue.Item{Node: uint32(k), Distance: v}, 5)
+	}
+
+	assert.Equal(t, 5, h.Len())
+
+	// No nearer than the furthest of the k nearest
+	assert.False(t, h.PushBounded(queue.Item{Distance: 10}, 5))
+
+	drained := h.Drain(nil)
+
+	assert.Equal(t, sortedItems()[:5], []float32{drained[0].Distance, drained[1].Distance, drained[2].Distance, drained[3].Distance, drained[4].Distance})
+
+	// Reuse without allocating
+	h.Reset()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		h.Reset()
+		for k, v := range items {
+			h.PushBounded(queue.Item{Node: uint32(k), Distance: v}, 5)
+		}
+	})
+
+	assert.Equal(t, float64(0), allocs)
+
+}
+
+// Bounded top-k insert into a max-heap PriorityQueue, as hnsw did before MaxHeap
+func pushBoundedPriorityQueue(h *queue.PriorityQueue, item *queue.Item, k int) {
+
+	heap.Push(h, item)
+
+	if h.Len() > k {
+		heap.Pop(h)
+	}
+
+}
+
+func Benchmark_PriorityQueue(b *testing.B) {
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+
+		h := &queue.PriorityQueue{Order: true}
+
+		for k, v := range items {
+			pushBoundedPriorityQueue(h, &queue.Item{Node: uint32(k), Distance: v}, 10)
+		}
+
+	}
+
+}
+
+func Benchmark_MaxHeap(b *testing.B) {
+
+	b.ReportAllocs()
+
+	h := queue.NewMaxHeap(10)
+
+	for i := 0; i < b.N; i++ {
+
+		h.Reset()
+
+		for k, v := range items {
+			h.PushBounded(queue.Item{Node: uint32(k), Distance: v}, 10)
+		}
+
+	}
+
+}
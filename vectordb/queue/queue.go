@@ -59,8 +59,8 @@ func (pq *PriorityQueue) Top() any {
 	return item
 }
 
-// update modifies the priority and value of an Item in the queue.
-func (pq *PriorityQueue) update(item *Item, node uint32, distance float32) {
+// Update modifies the priority and value of an Item in the queue.
+func (pq *PriorityQueue) Update(item *Item, node uint32, distance float32) {
 	item.Node = node
 	item.Distance = distance
 	heap.Fix(pq, item.Index)
@@ -0,0 +1,51 @@
+package queue
+
+import "sort"
+
+// Collect the k nearest items pushed, the results are returned nearest first. A zero TopK admits nothing, use NewTopK
+type TopK struct {
+	heap MaxHeap // Worst of the k nearest on top
+	k    int
+}
+
+func NewTopK(k int) *TopK {
+	return &TopK{heap: MaxHeap{items: make([]Item, 0, k)}, k: k}
+}
+
+// Capacity of the collector
+func (t *TopK) K() int { return t.k }
+
+func (t *TopK) Len() int { return t.heap.Len() }
+
+// Holds k items, a push must then beat the worst to be admitted
+func (t *TopK) Full() bool { return t.heap.Len() >= t.k }
+
+// Remove all items, keeping the capacity
+func (t *TopK) Reset() { t.heap.Reset() }
+
+// Admit `item` if fewer than k items are held or it is nearer than the worst, which is then evicted
+func (t *TopK) Push(item Item) bool {
+	return t.heap.PushBounded(item, t.k)
+}
+
+// Return the furthest of the items held, false if empty
+func (t *TopK) Worst() (Item, bool) {
+	return t.heap.Top()
+}
+
+// Items nearest first, the collector is left unchanged
+func (t *TopK) Sorted() []Item {
+
+	items := make([]Item, t.heap.Len())
+	copy(items, t.heap.Items())
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Distance < items[j].Distance })
+
+	return items
+
+}
+
+// Remove every item, appended to dst nearest first
+func (t *TopK) Drain(dst []Item) []Item {
+	return t.heap.Drain(dst)
+}
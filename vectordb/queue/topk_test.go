@@ -0,0 +1,55 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TopK(t *testing.T) {
+
+	topK := queue.NewTopK(5)
+
+	assert.Equal(t, 5, topK.K())
+	assert.False(t, topK.Full())
+
+	_, ok := topK.Worst()
+	assert.False(t, ok)
+
+	for k, v := range items {
+		topK.Push(queue.Item{Node: uint32(k), Distance: v})
+	}
+
+	assert.Equal(t, 5, topK.Len())
+	assert.True(t, topK.Full())
+
+	// Worst of the 5 nearest
+	worst, ok := topK.Worst()
+	assert.True(t, ok)
+	assert.Equal(t, float32(0.234), worst.Distance)
+
+	// Only admitted if it beats the worst
+	assert.False(t, topK.Push(queue.Item{Node: 100, Distance: 0.234}))
+	assert.True(t, topK.Push(queue.Item{Node: 101, Distance: 0.0002}))
+
+	sorted := topK.Sorted()
+	assert.Equal(t, 5, topK.Len())
+
+	expected := []float32{0.0002, 0.001, 0.020391, 0.0534, 0.193}
+
+	for i := range expected {
+		assert.Equal(t, expected[i], sorted[i].Distance)
+	}
+
+	assert.Equal(t, uint32(101), sorted[0].Node)
+
+	assert.Equal(t, sorted, topK.Drain(nil))
+	assert.Equal(t, 0, topK.Len())
+
+	// Zero value admits nothing
+	var empty queue.TopK
+	assert.False(t, empty.Push(queue.Item{Distance: 1}))
+	assert.Equal(t, 0, len(empty.Sorted()))
+
+}
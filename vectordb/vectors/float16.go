@@ -0,0 +1,168 @@
+package vectors
+
+import "math"
+
+// Convert an IEEE 754 half precision (float16) value to float32
+func Float16ToFloat32(h uint16) float32 {
+
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+
+	switch exp {
+
+	case 0:
+		// Zero or subnormal, value = mant * 2^-24
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+
+		f := float32(mant) / (1 << 24)
+
+		if sign != 0 {
+			return -f
+		}
+
+		return f
+
+	case 0x1f:
+		// Infinity or NaN
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+
+	}
+
+	// Normal, re-bias the exponent from 15 to 127
+	return math.Float32frombits(sign | (exp+112)<<23 | mant<<13)
+
+}
+
+// Convert a float32 to IEEE 754 half precision (float16), rounding to nearest even. Values beyond the float16 range
+// become infinity
+func Float32ToFloat16(f float32) uint16 {
+
+	bits := math.Float32bits(f)
+
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+
+	// Infinity or NaN, keeping NaN quiet
+	if exp == 0xff {
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+
+	// Re-bias the exponent from 127 to 15
+	e := exp - 112
+
+	if e >= 0x1f {
+		return sign | 0x7c00
+	}
+
+	if e <= 0 {
+
+		// Too small for a subnormal, rounds to zero
+		if e < -10 {
+			return sign
+		}
+
+		// Subnormal, value = half * 2^-24, including the implicit leading bit
+		mant |= 0x800000
+		shift := uint32(14 - e)
+
+		half := mant >> shift
+		rem := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+
+		return sign | uint16(half)
+
+	}
+
+	half := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1fff
+
+	// Rounding may carry into the exponent, which is still correct (up to infinity)
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++
+	}
+
+	return sign | uint16(half)
+
+}
+
+// Convert a bfloat16 value (the upper 16 bits of a float32) to float32
+func BFloat16ToFloat32(b uint16) float32 {
+	return math.Float32frombits(uint32(b) << 16)
+}
+
+// Convert a float32 to bfloat16, rounding to nearest even
+func Float32ToBFloat16(f float32) uint16 {
+
+	bits := math.Float32bits(f)
+
+	// Keep NaN quiet, rounding could otherwise carry it to infinity
+	if bits&0x7fffffff > 0x7f800000 {
+		return uint16(bits>>16) | 0x40
+	}
+
+	return uint16((bits + 0x7fff + (bits>>16)&1) >> 16)
+
+}
+
+// Convert a vector to float16
+func ToFloat16(v []float32) (h []uint16) {
+
+	h = make([]uint16, len(v))
+
+	for i := range v {
+		h[i] = Float32ToFloat16(v[i])
+	}
+
+	return h
+
+}
+
+// Convert a float16 vector to float32
+func FromFloat16(h []uint16) (v []float32) {
+
+	v = make([]float32, len(h))
+
+	for i := range h {
+		v[i] = Float16ToFloat32(h[i])
+	}
+
+	return v
+
+}
+
+// Convert a vector to bfloat16
+func ToBFloat16(v []float32) (b []uint16) {
+
+	b = make([]uint16, len(v))
+
+	for i := range v {
+		b[i] = Float32ToBFloat16(v[i])
+	}
+
+	return b
+
+}
+
+// Convert a bfloat16 vector to float32
+func FromBFloat16(b []uint16) (v []float32) {
+
+	v = make([]float32, len(b))
+
+	for i := range b {
+		v[i] = BFloat16ToFloat32(b[i])
+	}
+
+	return v
+
+}
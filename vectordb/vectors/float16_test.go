@@ -0,0 +1,81 @@
+package vectors_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Float16ToFloat32(t *testing.T) {
+
+	assert.Equal(t, float32(1), vectors.Float16ToFloat32(0x3c00))
+	assert.Equal(t, float32(-0.099975586), vectors.Float16ToFloat32(0xae66))
+
+	// Smallest subnormal, 2^-24
+	assert.Equal(t, float32(math.Ldexp(1, -24)), vectors.Float16ToFloat32(0x0001))
+
+	assert.True(t, math.Signbit(float64(vectors.Float16ToFloat32(0x8000))))
+	assert.True(t, math.IsInf(float64(vectors.Float16ToFloat32(0x7c00)), 1))
+	assert.True(t, math.IsInf(float64(vectors.Float16ToFloat32(0xfc00)), -1))
+	assert.True(t, math.IsNaN(float64(vectors.Float16ToFloat32(0x7e00))))
+
+}
+
+func Test_Float32ToFloat16(t *testing.T) {
+
+	assert.Equal(t, uint16(0x3c00), vectors.Float32ToFloat16(1))
+	assert.Equal(t, uint16(0xc000), vectors.Float32ToFloat16(-2))
+	assert.Equal(t, uint16(0x7bff), vectors.Float32ToFloat16(65504))
+	assert.Equal(t, uint16(0x0001), vectors.Float32ToFloat16(float32(math.Ldexp(1, -24))))
+	assert.Equal(t, uint16(0x0000), vectors.Float32ToFloat16(float32(math.Ldexp(1, -26))))
+	assert.Equal(t, uint16(0x8000), vectors.Float32ToFloat16(float32(math.Copysign(0, -1))))
+
+	// Overflow, infinity and NaN
+	assert.Equal(t, uint16(0x7c00), vectors.Float32ToFloat16(70000))
+	assert.Equal(t, uint16(0xfc00), vectors.Float32ToFloat16(float32(math.Inf(-1))))
+	assert.Equal(t, uint16(0x7e00), vectors.Float32ToFloat16(float32(math.NaN())))
+
+	// Round to nearest even, 1 + 2^-11 is halfway between 1 and the next float16
+	assert.Equal(t, uint16(0x3c00), vectors.Float32ToFloat16(1+float32(math.Ldexp(1, -11))))
+	assert.Equal(t, uint16(0x3c01), vectors.Float32ToFloat16(1+float32(math.Ldexp(1, -11))+float32(math.Ldexp(1, -20))))
+
+	// Every finite float16 survives a roundtrip
+	for h := 0; h < 0x10000; h++ {
+		if h&0x7c00 == 0x7c00 {
+			continue
+		}
+		assert.Equal(t, uint16(h), vectors.Float32ToFloat16(vectors.Float16ToFloat32(uint16(h))))
+	}
+
+}
+
+func Test_BFloat16(t *testing.T) {
+
+	assert.Equal(t, uint16(0x3f80), vectors.Float32ToBFloat16(1))
+	assert.Equal(t, float32(1), vectors.BFloat16ToFloat32(0x3f80))
+	assert.Equal(t, float32(-2), vectors.BFloat16ToFloat32(0xc000))
+
+	// 8 bits of mantissa, 1 + 2^-8 is halfway and rounds to even
+	assert.Equal(t, uint16(0x3f80), vectors.Float32ToBFloat16(1+float32(math.Ldexp(1, -8))))
+	assert.Equal(t, uint16(0x3f81), vectors.Float32ToBFloat16(1+float32(math.Ldexp(1, -7))))
+
+	assert.True(t, math.IsNaN(float64(vectors.BFloat16ToFloat32(vectors.Float32ToBFloat16(float32(math.NaN()))))))
+
+}
+
+func Test_Float16Vectors(t *testing.T) {
+
+	v, err := vectors.GenerateRandomVectors(1, 64)
+	assert.Nil(t, err)
+
+	for i, f := range vectors.FromFloat16(vectors.ToFloat16(v[0])) {
+		assert.InDelta(t, v[0][i], f, 1e-3)
+	}
+
+	for i, f := range vectors.FromBFloat16(vectors.ToBFloat16(v[0])) {
+		assert.InDelta(t, v[0][i], f, 1e-2)
+	}
+
+}
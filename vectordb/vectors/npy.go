@@ -0,0 +1,432 @@
+package vectors
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Loader and writer for the NumPy .npy format (https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html)
+// as written by `numpy.save`, float32, float16 and float64 matrices are loaded and converted to float32.
+
+var npyMagic = []byte("\x93NUMPY")
+
+var (
+	npyDescr        = regexp.MustCompile(`['"]descr['"]\s*:\s*['"]([^'"]*)['"]`)
+	npyFortranOrder = regexp.MustCompile(`['"]fortran_order['"]\s*:\s*(True|False)`)
+	npyShape        = regexp.MustCompile(`['"]shape['"]\s*:\s*\(([^)]*)\)`)
+)
+
+// Types supported by SaveNPY
+type NPYElement interface {
+	float32 | float64 | int32 | int64 | uint32 | uint64
+}
+
+// Load a 1 or 2 dimensional .npy file as vectors, each row is a vector (a 1 dimensional array is a single vector)
+//
+// Rows share a single backing array, as returned by LoadNPYFlat.
+func LoadNPY(filename string) (vectors [][]float32, err error) {
+
+	data, shape, err := LoadNPYFlat(filename)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, cols := 1, 0
+
+	switch len(shape) {
+	case 1:
+		cols = shape[0]
+	case 2:
+		rows, cols = shape[0], shape[1]
+	default:
+		return nil, fmt.Errorf("expected a 1 or 2 dimensional array, got shape %v", shape)
+	}
+
+	vectors = make([][]float32, rows)
+
+	for i := range vectors {
+		vectors[i] = data[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+
+	return vectors, nil
+
+}
+
+// Load a .npy file as a flat float32 array in row-major (C) order, with the shape of the array
+func LoadNPYFlat(filename string) (data []float32, shape []int, err error) {
+
+	file, err := os.Open(filename)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return readNPY(bufio.NewReader(file), info.Size())
+
+}
+
+// Read a .npy stream as a flat float32 array in row-major (C) order, with the shape of the array
+func ReadNPY(r io.Reader) (data []float32, shape []int, err error) {
+
+	return readNPY(r, -1)
+
+}
+
+// Save a matrix as a 2 dimensional .npy file (version 1.0, little-endian, C order), rows must share the same length
+func SaveNPY[T NPYElement](filename string, matrix [][]T) (err error) {
+
+	file, err := os.Create(filename)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	err = WriteNPY(writer, matrix)
+
+	if err != nil {
+		return err
+	}
+
+	err = writer.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+
+}
+
+// Write a matrix as a 2 dimensional .npy stream (version 1.0, little-endian, C order)
+func WriteNPY[T NPYElement](w io.Writer, matrix [][]T) (err error) {
+
+	cols := 0
+
+	if len(matrix) > 0 {
+		cols = len(matrix[0])
+	}
+
+	for i := range matrix {
+		if len(matrix[i]) != cols {
+			return fmt.Errorf("row %d has %d columns, expected %d", i, len(matrix[i]), cols)
+		}
+	}
+
+	var v T
+
+	var descr string
+
+	switch any(v).(type) {
+	case float32:
+		descr = "<f4"
+	case float64:
+		descr = "<f8"
+	case int32:
+		descr = "<i4"
+	case int64:
+		descr = "<i8"
+	case uint32:
+		descr = "<u4"
+	case uint64:
+		descr = "<u8"
+	}
+
+	err = writeNPYHeader(w, descr, []int{len(matrix), cols})
+
+	if err != nil {
+		return err
+	}
+
+	for i := range matrix {
+
+		err = binary.Write(w, binary.LittleEndian, matrix[i])
+
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+
+}
+
+// Private functions
+
+// Read a .npy stream of `size` bytes, -1 if unknown. The shape read from the header is checked against the size before
+// the data is allocated, a stream of unknown size is read as it arrives
+func readNPY(r io.Reader, size int64) (data []float32, shape []int, err error) {
+
+	descr, fortranOrder, shape, err := readNPYHeader(r, size)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(descr) != 3 {
+		return nil, nil, fmt.Errorf("unsupported dtype (%s)", descr)
+	}
+
+	var order binary.ByteOrder
+
+	switch descr[0] {
+	case '<', '|', '=':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	default:
+		return nil, nil, fmt.Errorf("unsupported byte order (%s)", descr)
+	}
+
+	var elemSize int
+
+	switch descr[1:] {
+	case "f2":
+		elemSize = 2
+	case "f4":
+		elemSize = 4
+	case "f8":
+		elemSize = 8
+	default:
+		return nil, nil, fmt.Errorf("unsupported dtype (%s), expected float16, float32 or float64", descr)
+	}
+
+	count := 1
+
+	for _, v := range shape {
+
+		if v != 0 && count > math.MaxInt/elemSize/v {
+			return nil, nil, fmt.Errorf("shape %v is too large", shape)
+		}
+
+		count *= v
+
+	}
+
+	var raw []byte
+
+	if size >= 0 {
+
+		if int64(count*elemSize) > size {
+			return nil, nil, fmt.Errorf("shape %v needs %d bytes, the file has %d", shape, count*elemSize, size)
+		}
+
+		raw = make([]byte, count*elemSize)
+		_, err = io.ReadFull(r, raw)
+
+	} else {
+
+		raw, err = io.ReadAll(io.LimitReader(r, int64(count*elemSize)))
+
+		if err == nil && len(raw) < count*elemSize {
+			err = io.ErrUnexpectedEOF
+		}
+
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data = make([]float32, count)
+
+	for i := range data {
+
+		switch elemSize {
+		case 2:
+			data[i] = Float16ToFloat32(order.Uint16(raw[i*2:]))
+		case 4:
+			data[i] = math.Float32frombits(order.Uint32(raw[i*4:]))
+		case 8:
+			data[i] = float32(math.Float64frombits(order.Uint64(raw[i*8:])))
+		}
+
+	}
+
+	// Column-major arrays are transposed to row-major
+	if fortranOrder && len(shape) == 2 {
+
+		rows, cols := shape[0], shape[1]
+		transposed := make([]float32, count)
+
+		for i := 0; i < rows; i++ {
+			for i2 := 0; i2 < cols; i2++ {
+				transposed[i*cols+i2] = data[i2*rows+i]
+			}
+		}
+
+		data = transposed
+
+	} else if fortranOrder && len(shape) > 2 {
+		return nil, nil, errors.New("fortran order only supported for 1 or 2 dimensional arrays")
+	}
+
+	return data, shape, nil
+
+}
+
+// Header of a stream of `size` bytes, -1 if unknown
+func readNPYHeader(r io.Reader, size int64) (descr string, fortranOrder bool, shape []int, err error) {
+
+	preamble := make([]byte, 8)
+
+	_, err = io.ReadFull(r, preamble)
+
+	if err != nil {
+		return
+	}
+
+	if !bytes.Equal(preamble[:6], npyMagic) {
+		err = errors.New("not a .npy file")
+		return
+	}
+
+	var headerLen uint32
+
+	// Version 1.0 uses a 2 byte header length, 2.0 and 3.0 use 4 bytes
+	switch preamble[6] {
+	case 1:
+		var l uint16
+		err = binary.Read(r, binary.LittleEndian, &l)
+		headerLen = uint32(l)
+	case 2, 3:
+		err = binary.Read(r, binary.LittleEndian, &headerLen)
+	default:
+		err = fmt.Errorf("unsupported .npy version (%d.%d)", preamble[6], preamble[7])
+	}
+
+	if err != nil {
+		return
+	}
+
+	if size >= 0 && int64(headerLen) > size {
+		err = fmt.Errorf("header length (%d) exceeds the file size", headerLen)
+		return
+	}
+
+	header := make([]byte, headerLen)
+
+	_, err = io.ReadFull(r, header)
+
+	if err != nil {
+		return
+	}
+
+	match := npyDescr.FindSubmatch(header)
+
+	if match == nil {
+		err = errors.New("missing descr in .npy header")
+		return
+	}
+
+	descr = string(match[1])
+
+	match = npyFortranOrder.FindSubmatch(header)
+
+	if match == nil {
+		err = errors.New("missing fortran_order in .npy header")
+		return
+	}
+
+	fortranOrder = string(match[1]) == "True"
+
+	match = npyShape.FindSubmatch(header)
+
+	if match == nil {
+		err = errors.New("missing shape in .npy header")
+		return
+	}
+
+	shape = make([]int, 0)
+
+	for _, dim := range strings.Split(string(match[1]), ",") {
+
+		dim = strings.TrimSpace(dim)
+
+		// A 1 dimensional shape has a trailing comma, (n,)
+		if dim == "" {
+			continue
+		}
+
+		var v int
+
+		v, err = strconv.Atoi(dim)
+
+		if err != nil || v < 0 {
+			err = fmt.Errorf("invalid shape in .npy header (%s)", match[1])
+			return
+		}
+
+		shape = append(shape, v)
+
+	}
+
+	return
+
+}
+
+func writeNPYHeader(w io.Writer, descr string, shape []int) (err error) {
+
+	dims := make([]string, len(shape))
+
+	for i, v := range shape {
+		dims[i] = strconv.Itoa(v)
+	}
+
+	shapeStr := strings.Join(dims, ", ")
+
+	if len(shape) == 1 {
+		shapeStr += ","
+	}
+
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, shapeStr)
+
+	// Pad with spaces so the data is 64 byte aligned, terminated by a newline
+	total := len(npyMagic) + 2 + 2 + len(header) + 1
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+
+	_, err = w.Write(npyMagic)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte{1, 0})
+
+	if err != nil {
+		return err
+	}
+
+	err = binary.Write(w, binary.LittleEndian, uint16(len(header)))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, header)
+
+	return err
+
+}
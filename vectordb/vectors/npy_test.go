@@ -0,0 +1,160 @@
+package vectors_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Build a .npy v1.0 file in the layout written by numpy.save
+func npyFile(header string, data any, order binary.ByteOrder) []byte {
+
+	var buf bytes.Buffer
+
+	total := 10 + len(header) + 1
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+
+	buf.WriteString("\x93NUMPY")
+	buf.Write([]byte{1, 0})
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	binary.Write(&buf, order, data)
+
+	return buf.Bytes()
+
+}
+
+func writeNPY(t *testing.T, data []byte) string {
+
+	filename := filepath.Join(t.TempDir(), "test.npy")
+
+	err := os.WriteFile(filename, data, 0o644)
+	assert.Nil(t, err)
+
+	return filename
+
+}
+
+func Test_SaveLoadNPY(t *testing.T) {
+
+	v, err := vectors.GenerateRandomVectors(10, 7)
+	assert.Nil(t, err)
+
+	filename := filepath.Join(t.TempDir(), "vectors.npy")
+
+	err = vectors.SaveNPY(filename, v)
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+
+	// Data must start on a 64 byte boundary
+	assert.True(t, bytes.HasPrefix(data, []byte("\x93NUMPY\x01\x00")))
+	assert.Equal(t, 0, (len(data)-10*7*4)%64)
+	assert.Contains(t, string(data[:128]), "{'descr': '<f4', 'fortran_order': False, 'shape': (10, 7), }")
+
+	v2, err := vectors.LoadNPY(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, v, v2)
+
+	flat, shape, err := vectors.LoadNPYFlat(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{10, 7}, shape)
+	assert.Equal(t, v[3][2], flat[3*7+2])
+
+}
+
+func Test_SaveNPYInt(t *testing.T) {
+
+	filename := filepath.Join(t.TempDir(), "results.npy")
+
+	err := vectors.SaveNPY(filename, [][]int64{{1, 2}, {3, 4}, {5, 6}})
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+
+	assert.Contains(t, string(data[:128]), "{'descr': '<i8', 'fortran_order': False, 'shape': (3, 2), }")
+	assert.Equal(t, 128+3*2*8, len(data))
+	assert.Equal(t, uint64(6), binary.LittleEndian.Uint64(data[len(data)-8:]))
+
+	err = vectors.SaveNPY(filename, [][]float32{{1, 2}, {3}})
+	assert.NotNil(t, err)
+
+}
+
+func Test_LoadNPYFloat16(t *testing.T) {
+
+	// 1.0, -2.0, 0.5, 65504 (max float16)
+	data := npyFile("{'descr': '<f2', 'fortran_order': False, 'shape': (2, 2), }", []uint16{0x3c00, 0xc000, 0x3800, 0x7bff}, binary.LittleEndian)
+
+	v, err := vectors.LoadNPY(writeNPY(t, data))
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]float32{{1, -2}, {0.5, 65504}}, v)
+
+}
+
+func Test_LoadNPYFloat64FortranOrder(t *testing.T) {
+
+	// Column-major, big-endian [[1, 2, 3], [4, 5, 6]]
+	data := npyFile("{'descr': '>f8', 'fortran_order': True, 'shape': (2, 3), }", []float64{1, 4, 2, 5, 3, 6}, binary.BigEndian)
+
+	v, err := vectors.LoadNPY(writeNPY(t, data))
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]float32{{1, 2, 3}, {4, 5, 6}}, v)
+
+}
+
+func Test_LoadNPY1D(t *testing.T) {
+
+	data := npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (3,), }", []float32{1, 2, 3}, binary.LittleEndian)
+
+	v, err := vectors.LoadNPY(writeNPY(t, data))
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]float32{{1, 2, 3}}, v)
+
+}
+
+func Test_LoadNPYInvalid(t *testing.T) {
+
+	data := npyFile("{'descr': '<i4', 'fortran_order': False, 'shape': (3,), }", []int32{1, 2, 3}, binary.LittleEndian)
+
+	_, err := vectors.LoadNPY(writeNPY(t, data))
+	assert.NotNil(t, err)
+
+	_, err = vectors.LoadNPY(writeNPY(t, []byte("not numpy")))
+	assert.NotNil(t, err)
+
+	// Truncated data
+	data = npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (4,), }", []float32{1, 2, 3}, binary.LittleEndian)
+
+	_, err = vectors.LoadNPY(writeNPY(t, data))
+	assert.NotNil(t, err)
+
+}
+
+func Test_LoadNPYShapeTooLarge(t *testing.T) {
+
+	// Shapes larger than the file, or overflowing, fail before the data is allocated
+	for _, shape := range []string{"(1000000000, 1000000000)", "(9223372036854775807, 2)", "(4611686018427387904, 4)"} {
+
+		data := npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': "+shape+", }", []float32{1, 2, 3}, binary.LittleEndian)
+
+		_, err := vectors.LoadNPY(writeNPY(t, data))
+		assert.NotNil(t, err, shape)
+
+		_, _, err = vectors.ReadNPY(bytes.NewReader(data))
+		assert.NotNil(t, err, shape)
+
+	}
+
+}
@@ -0,0 +1,474 @@
+package vectors
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// Readers and writers for the TEXMEX vector formats (http://corpus-texmex.irisa.fr/) used by SIFT1M, GIST1M and
+// ANN-Benchmarks. Each vector is stored as a little-endian int32 dimension, followed by the components:
+//
+//	.fvecs float32 components, base and query vectors
+//	.ivecs int32 components, ground truth (ids of the nearest neighbours)
+//	.bvecs uint8 components, byte vectors (SIFT1B)
+
+// Component types supported by the TEXMEX formats
+type VecsElement interface {
+	float32 | int32 | uint8
+}
+
+// Streaming reader, returns one vector per call to Read
+type VecsReader[T VecsElement] struct {
+	reader   io.Reader
+	buf      []byte
+	elemSize int
+	size     int64 // Bytes left to read, -1 if unknown
+}
+
+// Streaming writer, call Flush once all vectors are written
+type VecsWriter[T VecsElement] struct {
+	writer   *bufio.Writer
+	buf      []byte
+	elemSize int
+}
+
+func NewFvecsReader(r io.Reader) *VecsReader[float32] {
+	return newVecsReader[float32](r, -1)
+}
+
+func NewIvecsReader(r io.Reader) *VecsReader[int32] {
+	return newVecsReader[int32](r, -1)
+}
+
+func NewBvecsReader(r io.Reader) *VecsReader[uint8] {
+	return newVecsReader[uint8](r, -1)
+}
+
+func NewFvecsWriter(w io.Writer) *VecsWriter[float32] {
+	return newVecsWriter[float32](w)
+}
+
+func NewIvecsWriter(w io.Writer) *VecsWriter[int32] {
+	return newVecsWriter[int32](w)
+}
+
+func NewBvecsWriter(w io.Writer) *VecsWriter[uint8] {
+	return newVecsWriter[uint8](w)
+}
+
+// Read the next vector, returns io.EOF once all vectors are read. The dimension read from the stream is checked against
+// the bytes left in a file before the vector is allocated, a stream of unknown size is read as it arrives
+func (r *VecsReader[T]) Read() (vector []T, err error) {
+
+	var header [4]byte
+
+	_, err = io.ReadFull(r.reader, header[:])
+
+	if err != nil {
+		// A partial dimension header is a truncated file, not a clean end of stream
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("truncated vector dimension")
+		}
+		return nil, err
+	}
+
+	dim := int32(binary.LittleEndian.Uint32(header[:]))
+
+	if dim < 0 {
+		return nil, fmt.Errorf("invalid vector dimension (%d)", dim)
+	}
+
+	size := int(dim) * r.elemSize
+
+	if r.size >= 0 {
+
+		r.size -= 4
+
+		if int64(size) > r.size {
+			return nil, fmt.Errorf("vector dimension (%d) needs %d bytes, the file has %d left", dim, size, r.size)
+		}
+
+		r.size -= int64(size)
+
+	}
+
+	if cap(r.buf) >= size || r.size >= 0 {
+
+		if cap(r.buf) < size {
+			r.buf = make([]byte, size)
+		}
+
+		r.buf = r.buf[:size]
+
+		_, err = io.ReadFull(r.reader, r.buf)
+
+	} else {
+
+		r.buf, err = io.ReadAll(io.LimitReader(r.reader, int64(size)))
+
+		if err == nil && len(r.buf) < size {
+			err = io.ErrUnexpectedEOF
+		}
+
+	}
+
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	vector = make([]T, dim)
+	decodeVecs(r.buf, vector)
+
+	return vector, nil
+
+}
+
+// Write a single vector
+func (w *VecsWriter[T]) Write(vector []T) (err error) {
+
+	size := 4 + len(vector)*w.elemSize
+
+	if cap(w.buf) < size {
+		w.buf = make([]byte, size)
+	}
+
+	w.buf = w.buf[:size]
+
+	binary.LittleEndian.PutUint32(w.buf, uint32(len(vector)))
+	encodeVecs(w.buf[4:], vector)
+
+	_, err = w.writer.Write(w.buf)
+
+	return err
+
+}
+
+// Flush any buffered vectors to the underlying writer
+func (w *VecsWriter[T]) Flush() error {
+	return w.writer.Flush()
+}
+
+// Random access to the vectors of an open .fvecs file, all vectors must have the same dimension
+type FvecsFile struct {
+	file *os.File
+	dim  int
+	num  int
+}
+
+// Open a .fvecs file for random access, the dimension is read from the first vector
+func OpenFvecsFile(filename string) (f *FvecsFile, err error) {
+
+	file, err := os.Open(filename)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	var header [4]byte
+
+	_, err = file.ReadAt(header[:], 0)
+
+	if err != nil {
+		file.Close()
+		return nil, errors.New("truncated vector dimension")
+	}
+
+	dim := int(int32(binary.LittleEndian.Uint32(header[:])))
+
+	if dim <= 0 || info.Size()%int64(4+dim*4) != 0 {
+		file.Close()
+		return nil, fmt.Errorf("invalid vector dimension (%d) for file size %d", dim, info.Size())
+	}
+
+	return &FvecsFile{file: file, dim: dim, num: int(info.Size() / int64(4+dim*4))}, nil
+
+}
+
+// Read vector `i`
+func (f *FvecsFile) ReadAt(i int) (vector []float32, err error) {
+
+	if i < 0 || i >= f.num {
+		return nil, fmt.Errorf("vector %d out of range (%d vectors)", i, f.num)
+	}
+
+	buf := make([]byte, f.dim*4)
+
+	_, err = f.file.ReadAt(buf, int64(i)*int64(4+f.dim*4)+4)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vector = make([]float32, f.dim)
+	decodeVecs(buf, vector)
+
+	return vector, nil
+
+}
+
+// Number of vectors in the file
+func (f *FvecsFile) Len() int {
+	return f.num
+}
+
+// Dimension of each vector
+func (f *FvecsFile) Dim() int {
+	return f.dim
+}
+
+func (f *FvecsFile) Close() error {
+	return f.file.Close()
+}
+
+// Read all vectors from a .fvecs file
+func ReadFvecs(filename string) ([][]float32, error) {
+	return readVecsRange[float32](filename, 0, -1)
+}
+
+// Read `num` vectors from a .fvecs file starting from vector `start`, if num < 0 all remaining vectors are read
+func ReadFvecsRange(filename string, start int, num int) ([][]float32, error) {
+	return readVecsRange[float32](filename, start, num)
+}
+
+func WriteFvecs(filename string, vectors [][]float32) error {
+	return writeVecs(filename, vectors)
+}
+
+// Read all vectors from a .ivecs file
+func ReadIvecs(filename string) ([][]int32, error) {
+	return readVecsRange[int32](filename, 0, -1)
+}
+
+// Read `num` vectors from a .ivecs file starting from vector `start`, if num < 0 all remaining vectors are read
+func ReadIvecsRange(filename string, start int, num int) ([][]int32, error) {
+	return readVecsRange[int32](filename, start, num)
+}
+
+func WriteIvecs(filename string, vectors [][]int32) error {
+	return writeVecs(filename, vectors)
+}
+
+// Read all vectors from a .bvecs file
+func ReadBvecs(filename string) ([][]uint8, error) {
+	return readVecsRange[uint8](filename, 0, -1)
+}
+
+// Read `num` vectors from a .bvecs file starting from vector `start`, if num < 0 all remaining vectors are read
+func ReadBvecsRange(filename string, start int, num int) ([][]uint8, error) {
+	return readVecsRange[uint8](filename, start, num)
+}
+
+func WriteBvecs(filename string, vectors [][]uint8) error {
+	return writeVecs(filename, vectors)
+}
+
+// Convert byte vectors (.bvecs) to float32 for indexing
+func BvecsToFloat32(vectors [][]uint8) [][]float32 {
+
+	converted := make([][]float32, len(vectors))
+
+	for i := range vectors {
+
+		converted[i] = make([]float32, len(vectors[i]))
+
+		for i2, v := range vectors[i] {
+			converted[i][i2] = float32(v)
+		}
+	}
+
+	return converted
+
+}
+
+// Private functions
+
+// Reader of a stream of `size` bytes, -1 if unknown
+func newVecsReader[T VecsElement](r io.Reader, size int64) *VecsReader[T] {
+	return &VecsReader[T]{reader: bufio.NewReader(r), elemSize: vecsElemSize[T](), size: size}
+}
+
+func newVecsWriter[T VecsElement](w io.Writer) *VecsWriter[T] {
+	return &VecsWriter[T]{writer: bufio.NewWriter(w), elemSize: vecsElemSize[T]()}
+}
+
+func vecsElemSize[T VecsElement]() int {
+
+	var v T
+
+	switch any(v).(type) {
+	case uint8:
+		return 1
+	default:
+		return 4
+	}
+
+}
+
+func decodeVecs[T VecsElement](buf []byte, vector []T) {
+
+	switch v := any(vector).(type) {
+	case []float32:
+		for i := range v {
+			v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+		}
+	case []int32:
+		for i := range v {
+			v[i] = int32(binary.LittleEndian.Uint32(buf[i*4:]))
+		}
+	case []uint8:
+		copy(v, buf)
+	}
+
+}
+
+func encodeVecs[T VecsElement](buf []byte, vector []T) {
+
+	switch v := any(vector).(type) {
+	case []float32:
+		for i := range v {
+			binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v[i]))
+		}
+	case []int32:
+		for i := range v {
+			binary.LittleEndian.PutUint32(buf[i*4:], uint32(v[i]))
+		}
+	case []uint8:
+		copy(buf, v)
+	}
+
+}
+
+// Seek directly to vector `start`, all vectors in a TEXMEX file share the dimension of the first
+func readVecsRange[T VecsElement](filename string, start int, num int) (vectors [][]T, err error) {
+
+	if start < 0 {
+		return nil, fmt.Errorf("invalid start vector (%d)", start)
+	}
+
+	file, err := os.Open(filename)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	offset := int64(0)
+
+	if start > 0 {
+
+		var header [4]byte
+
+		_, err = io.ReadFull(file, header[:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		dim := int64(int32(binary.LittleEndian.Uint32(header[:])))
+		recordSize := 4 + dim*int64(vecsElemSize[T]())
+
+		offset = int64(start) * recordSize
+
+		_, err = file.Seek(offset, io.SeekStart)
+
+		if err != nil {
+			return nil, err
+		}
+
+	}
+
+	// Past the end of the file there are no vectors to read
+	size := info.Size() - offset
+
+	if size < 0 {
+		size = 0
+	}
+
+	reader := newVecsReader[T](file, size)
+
+	// Each vector takes at least its 4 byte dimension, `num` is only a limit
+	if num >= 0 {
+
+		capacity := int64(num)
+
+		if capacity > size/4 {
+			capacity = size / 4
+		}
+
+		vectors = make([][]T, 0, capacity)
+
+	}
+
+	for num < 0 || len(vectors) < num {
+
+		vector, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		vectors = append(vectors, vector)
+
+	}
+
+	return vectors, nil
+
+}
+
+func writeVecs[T VecsElement](filename string, vectors [][]T) (err error) {
+
+	file, err := os.Create(filename)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	writer := newVecsWriter[T](file)
+
+	for i := range vectors {
+
+		err = writer.Write(vectors[i])
+
+		if err != nil {
+			return err
+		}
+
+	}
+
+	err = writer.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+
+}
@@ -0,0 +1,189 @@
+package vectors_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws-samples/gofast-hnsw/vectordb/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FvecsLayout(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	writer := vectors.NewFvecsWriter(&buf)
+
+	err := writer.Write([]float32{1, 2})
+	assert.Nil(t, err)
+
+	err = writer.Flush()
+	assert.Nil(t, err)
+
+	// Little-endian int32 dimension, followed by float32 components
+	assert.Equal(t, []byte{2, 0, 0, 0, 0, 0, 0x80, 0x3f, 0, 0, 0, 0x40}, buf.Bytes())
+
+	reader := vectors.NewFvecsReader(&buf)
+
+	v, err := reader.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, []float32{1, 2}, v)
+
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+
+}
+
+func Test_FvecsReadWrite(t *testing.T) {
+
+	v, err := vectors.GenerateRandomVectors(100, 16)
+	assert.Nil(t, err)
+
+	filename := filepath.Join(t.TempDir(), "base.fvecs")
+
+	err = vectors.WriteFvecs(filename, v)
+	assert.Nil(t, err)
+
+	v2, err := vectors.ReadFvecs(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, v, v2)
+
+	// Range from the middle of the file
+	v3, err := vectors.ReadFvecsRange(filename, 40, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, v[40:50], v3)
+
+	// Range past the end returns the remaining vectors
+	v4, err := vectors.ReadFvecsRange(filename, 95, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, v[95:], v4)
+
+	v5, err := vectors.ReadFvecsRange(filename, 90, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, v[90:], v5)
+
+}
+
+func Test_FvecsFile(t *testing.T) {
+
+	v, err := vectors.GenerateRandomVectors(50, 8)
+	assert.Nil(t, err)
+
+	filename := filepath.Join(t.TempDir(), "base.fvecs")
+
+	err = vectors.WriteFvecs(filename, v)
+	assert.Nil(t, err)
+
+	f, err := vectors.OpenFvecsFile(filename)
+	assert.Nil(t, err)
+
+	defer f.Close()
+
+	assert.Equal(t, 50, f.Len())
+	assert.Equal(t, 8, f.Dim())
+
+	for _, i := range []int{0, 17, 49} {
+		v2, err := f.ReadAt(i)
+		assert.Nil(t, err)
+		assert.Equal(t, v[i], v2)
+	}
+
+	_, err = f.ReadAt(50)
+	assert.NotNil(t, err)
+
+}
+
+func Test_IvecsReadWrite(t *testing.T) {
+
+	v := [][]int32{{1, 2, 3}, {4, 5, 6}, {-1, 0, 1 << 30}}
+
+	filename := filepath.Join(t.TempDir(), "groundtruth.ivecs")
+
+	err := vectors.WriteIvecs(filename, v)
+	assert.Nil(t, err)
+
+	v2, err := vectors.ReadIvecs(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, v, v2)
+
+	v3, err := vectors.ReadIvecsRange(filename, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, v[1:2], v3)
+
+}
+
+func Test_BvecsReadWrite(t *testing.T) {
+
+	v := [][]uint8{{0, 128, 255, 1}, {2, 3, 4, 5}}
+
+	filename := filepath.Join(t.TempDir(), "base.bvecs")
+
+	err := vectors.WriteBvecs(filename, v)
+	assert.Nil(t, err)
+
+	v2, err := vectors.ReadBvecs(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, v, v2)
+
+	v3, err := vectors.ReadBvecsRange(filename, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, v[1:], v3)
+
+	assert.Equal(t, [][]float32{{0, 128, 255, 1}, {2, 3, 4, 5}}, vectors.BvecsToFloat32(v))
+
+}
+
+func Test_FvecsTruncated(t *testing.T) {
+
+	filename := filepath.Join(t.TempDir(), "truncated.fvecs")
+
+	err := vectors.WriteFvecs(filename, [][]float32{{1, 2, 3, 4}})
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+
+	err = os.WriteFile(filename, data[:len(data)-2], 0o644)
+	assert.Nil(t, err)
+
+	_, err = vectors.ReadFvecs(filename)
+	assert.NotNil(t, err)
+
+}
+
+func Test_FvecsOversizedDimension(t *testing.T) {
+
+	filename := filepath.Join(t.TempDir(), "oversized.fvecs")
+
+	// A dimension of 2^30 followed by a single component
+	data := []byte{0, 0, 0, 0x40, 0, 0, 0x80, 0x3f}
+
+	err := os.WriteFile(filename, data, 0o644)
+	assert.Nil(t, err)
+
+	_, err = vectors.ReadFvecs(filename)
+	assert.NotNil(t, err)
+
+	_, err = vectors.ReadFvecsRange(filename, 0, 1)
+	assert.NotNil(t, err)
+
+	// A stream of unknown size is read as it arrives
+	_, err = vectors.NewFvecsReader(bytes.NewReader(data)).Read()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// The number of vectors requested is only a limit
+	err = vectors.WriteFvecs(filename, [][]float32{{1, 2}, {3, 4}, {5, 6}})
+	assert.Nil(t, err)
+
+	vecs, err := vectors.ReadFvecsRange(filename, 1, 1<<40)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]float32{{3, 4}, {5, 6}}, vecs)
+
+	vecs, err = vectors.ReadFvecsRange(filename, 5, 1<<40)
+	assert.Nil(t, err)
+	assert.Empty(t, vecs)
+
+}